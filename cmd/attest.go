@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/kiptoonkipkurui/provavalidator/pkg/attestation"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +15,9 @@ var attestCmd = &cobra.Command{
 	// Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
+		if sbom.IsLocalSource(args[0]) {
+			return fmt.Errorf("attest needs an image in a registry, not %s", args[0])
+		}
 		results, err := attestation.VerifyImageAttestations(ctx, args[0], appCtx.AuthConfig)
 
 		if err != nil {
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestAttestRejectsLocalSource(t *testing.T) {
+	rootCmd.SetArgs([]string{"attest", "dir:/tmp"})
+	rootCmd.SetErr(io.Discard)
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "needs an image in a registry") {
+		t.Fatalf("expected a local source error, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheDir      string
+	cachePruneAll bool
+	cachePruneTTL time.Duration
+	sbomCacheDir  string
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk caches of OSV results and generated SBOMs",
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete expired OSV results and SBOMs from old tool versions (or all with --all)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := vuln.OpenCache(cacheDir, cachePruneTTL)
+		if err != nil {
+			return err
+		}
+		n, err := c.Prune(cachePruneAll)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cache entries from %s\n", n, c.Dir)
+
+		n, dir, err := sbom.PruneCache(sbomCacheDir, cachePruneAll)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cached SBOMs from %s\n", n, dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.PersistentFlags().StringVar(&cacheDir, "dir", "", "OSV cache directory (default: user cache dir)")
+	cacheCmd.PersistentFlags().StringVar(&sbomCacheDir, "sbom-dir", "", "SBOM cache directory (default: user cache dir)")
+	cachePruneCmd.Flags().BoolVar(&cachePruneAll, "all", false, "Remove every entry, not just expired ones")
+	cachePruneCmd.Flags().DurationVar(&cachePruneTTL, "ttl", vuln.DefaultCacheTTL, "Entries older than this are expired")
+
+	cacheCmd.AddCommand(cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
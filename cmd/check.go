@@ -2,32 +2,201 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/kiptoonkipkurui/provavalidator/pkg/attestation"
 	"github.com/kiptoonkipkurui/provavalidator/pkg/drift"
 	"github.com/kiptoonkipkurui/provavalidator/pkg/registry"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/report"
 	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
 	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
 	"github.com/spf13/cobra"
 )
 
+var (
+	checkFormat        string
+	checkOutput        string
+	checkSBOMTolerance float64
+	checkSBOMQuality   float64
+)
+
 var checkCmd = &cobra.Command{
 	Use:   "check Image",
 	Short: "Run all provenance validation checks on the specified image",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+		ctx := cmd.Context()
 		image := args[0]
-		image = "ghcr.io/sigstore/cosign:v2.4.0"
-		fmt.Println("Running all checks on:", image)
-		// orchestrate checks here
-		attestation.VerifyImageAttestations(ctx, image, appCtx.AuthConfig)
-		sbom.ExtractSBOM(ctx, image)
-		vuln.ScanVulnerabilities(ctx, image)
-		drift.DetectLayerDrift(ctx, image)
-		registry.FetchImageMetadata(ctx, image)
-		fmt.Println("All checks completed")
-		return nil
+
+		renderer, err := report.Lookup(checkFormat)
+		if err != nil {
+			return err
+		}
+		if checkSBOMTolerance < 0 || checkSBOMTolerance > 1 {
+			return fmt.Errorf("--sbom-tolerance must be between 0 and 1, got %g", checkSBOMTolerance)
+		}
+		if checkSBOMQuality < 0 || checkSBOMQuality > 100 {
+			return fmt.Errorf("--sbom-min-quality must be between 0 and 100, got %g", checkSBOMQuality)
+		}
+		policy, err := vulnPolicy()
+		if err != nil {
+			return err
+		}
+		enrichment, err := loadEnrichment()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.ErrOrStderr(), "Running all checks on:", image)
+		rep := report.New(image)
+
+		run := func(name string, fail report.Status, fn func() (string, error)) {
+			start := time.Now()
+			msg, err := fn()
+			c := report.Check{Name: name, Status: report.StatusPass, Message: msg, Duration: time.Since(start)}
+			if err != nil {
+				c.Status = fail
+				c.Message = err.Error()
+			}
+			rep.Add(c)
+		}
+		// attestations, drift and metadata come from the registry
+		local := sbom.IsLocalSource(image)
+		remote := func(name string, fail report.Status, fn func() (string, error)) {
+			if local {
+				rep.Add(report.Check{Name: name, Status: report.StatusSkipped, Message: "not available for local sources"})
+				return
+			}
+			run(name, fail, fn)
+		}
+
+		remote("attestation", report.StatusFail, func() (string, error) {
+			atts, err := attestation.VerifyImageAttestations(ctx, image, appCtx.AuthConfig)
+			return fmt.Sprintf("%d verified attestations", len(atts)), err
+		})
+		var resolved *sbom.ResolvedSBOM
+		run("sbom", report.StatusError, func() (string, error) {
+			s, err := sbom.ExtractSBOM(ctx, image)
+			if err != nil {
+				return "", err
+			}
+			resolved = s
+			return fmt.Sprintf("%d packages from %s SBOM (%s)", len(s.Packages), s.Source, s.Format), nil
+		})
+		if resolved != nil {
+			rep.Add(sbomQualityCheck(resolved, checkSBOMQuality))
+		}
+
+		if local {
+			rep.Add(report.Check{Name: "sbom-crosscheck", Status: report.StatusSkipped, Message: "not available for local sources"})
+		} else {
+			rep.Add(sbomCrossCheck(ctx, image, checkSBOMTolerance))
+		}
+
+		// scan the SBOM the sbom check produced; local sources are not
+		// cached, so extracting again would run the catalogers twice
+		start := time.Now()
+		var res *vuln.ScanResult
+		if resolved == nil {
+			err = errors.New("no SBOM to scan")
+		} else {
+			res, err = vuln.ScanSBOM(ctx, resolved, vuln.DefaultScanOptions())
+		}
+		if err != nil {
+			rep.Add(report.Check{Name: "vulnerabilities", Status: report.StatusError, Message: err.Error(), Duration: time.Since(start)})
+		} else {
+			vuln.Enrich(res.Findings, enrichment)
+			rep.SetScan(res.Findings, &res.Coverage)
+			if policy.Baseline != nil {
+				rep.SetDelta(baselinePath, vuln.Compare(policy.Baseline, res.Findings))
+			}
+			verdict := vuln.Evaluate(res.Findings, &res.Coverage, policy)
+			checks := report.ScanChecks(res.Findings, &verdict)
+			checks[0].Duration = time.Since(start)
+			rep.Add(checks...)
+		}
+
+		remote("drift", report.StatusFail, func() (string, error) {
+			return "", drift.DetectLayerDrift(ctx, image)
+		})
+		remote("metadata", report.StatusError, func() (string, error) {
+			md, err := registry.FetchImageMetadata(ctx, image)
+			if err != nil {
+				return "", err
+			}
+			return "manifest " + md.ManifestDigest, nil
+		})
+
+		return writeOutput(checkOutput, func(w io.Writer) error {
+			if err := renderer.Render(w, rep); err != nil {
+				return err
+			}
+			return rep.Err()
+		})
 	},
 }
+
+// sbomCrossCheck compares the attested SBOM, if the image has one, with an
+// SBOM generated from the image, to catch stale or incomplete attestations.
+func sbomCrossCheck(ctx context.Context, image string, tolerance float64) report.Check {
+	start := time.Now()
+	c := report.Check{Name: "sbom-crosscheck"}
+
+	attested, err := sbom.ExtractAttestedSBOM(ctx, nil, image)
+	if err == nil {
+		var generated *sbom.ResolvedSBOM
+		if generated, err = sbom.ExtractSBOM(ctx, image); err == nil {
+			r := sbom.CrossCheck(attested.Packages, generated.Packages, tolerance)
+			c.Status = report.StatusPass
+			if !r.Passed {
+				c.Status = report.StatusFail
+			}
+			c.Message = r.Summary()
+			for _, p := range r.MissingFromAttested {
+				c.Details = append(c.Details, fmt.Sprintf("not attested: %s %s (%s)", p.Name, p.Version, p.Type))
+			}
+			for _, v := range r.Stale {
+				c.Details = append(c.Details, fmt.Sprintf("stale: %s attested at %s, installed %s", v.Name, v.Before, v.After))
+			}
+			for _, p := range r.MissingFromImage {
+				c.Details = append(c.Details, fmt.Sprintf("not in image: %s %s (%s)", p.Name, p.Version, p.Type))
+			}
+		}
+	}
+
+	switch {
+	case errors.Is(err, attestation.ErrNoSBOMAttestation):
+		c.Status = report.StatusSkipped
+		c.Message = "image has no verified SBOM attestation"
+	case err != nil:
+		c.Status = report.StatusError
+		c.Message = err.Error()
+	}
+	c.Duration = time.Since(start)
+	return c
+}
+
+// sbomQualityCheck scores the SBOM the scan uses and fails below minScore.
+func sbomQualityCheck(s *sbom.ResolvedSBOM, minScore float64) report.Check {
+	q := sbom.ScoreQuality(s)
+	c := report.Check{Name: "sbom-quality", Status: report.StatusPass, Message: q.Summary()}
+	if q.Score < minScore {
+		c.Status = report.StatusFail
+		c.Message = fmt.Sprintf("%s, below the minimum of %g", q.Summary(), minScore)
+	}
+	for _, f := range q.Missing() {
+		c.Details = append(c.Details, fmt.Sprintf("%s: %d/%d (%.1f%%)", f.Field, f.Covered, f.Total, f.Coverage))
+	}
+	return c
+}
+
+func init() {
+	addPolicyFlags(checkCmd)
+	checkCmd.Flags().Float64Var(&checkSBOMQuality, "sbom-min-quality", 0, "Fail when the SBOM quality score (0-100, see sbom quality) is below this")
+	checkCmd.Flags().Float64Var(&checkSBOMTolerance, "sbom-tolerance", sbom.DefaultCrossCheckTolerance, "Fraction of packages the attested SBOM may miss or list stale before the cross-check fails")
+	checkCmd.Flags().StringVar(&checkFormat, "format", "text", "Output format (text|json|sarif|junit|markdown|html)")
+	checkCmd.Flags().StringVarP(&checkOutput, "output", "o", "", "Write the report to this file instead of stdout")
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import "testing"
+
+func TestCheckSharesVulnPolicyFlags(t *testing.T) {
+	// check fails on exactly what vuln would fail on for the same flags
+	for _, name := range []string{
+		"fail-on", "fail-on-score", "max-critical", "max-high", "max-medium", "max-low",
+		"fail-only-fixed", "baseline", "min-coverage", "kev", "epss", "fail-on-kev", "fail-on-epss",
+	} {
+		v, c := vulnCmd.Flags().Lookup(name), checkCmd.Flags().Lookup(name)
+		if v == nil || c == nil {
+			t.Errorf("--%s: registered on vuln %v, on check %v", name, v != nil, c != nil)
+			continue
+		}
+		if v.DefValue != c.DefValue {
+			t.Errorf("--%s: vuln defaults to %q, check to %q", name, v.DefValue, c.DefValue)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// writeOutput runs write against the file named by --output, or stdout when
+// path is empty or "-". The file is written even when write returns a
+// policy error, and that error is passed through.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" || path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create output: %w", err)
+	}
+	werr := write(f)
+	if err := f.Close(); err != nil && werr == nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return werr
+}
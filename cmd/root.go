@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/kiptoonkipkurui/provavalidator/pkg/registryauth"
@@ -17,7 +16,17 @@ var (
 var rootCmd = &cobra.Command{
 	Use:   "provavalidator",
 	Short: "A tool to validate software supply chain provenance",
-	Long:  `Provavalidator is a command-line tool that helps validate the authenticity and integrity of software supply chain provenance.`,
+	Long: `Provavalidator is a command-line tool that helps validate the authenticity and integrity of software supply chain provenance.
+
+Wherever an IMAGE is expected, a local source can be given instead:
+  oci-layout:PATH       OCI image layout directory
+  docker-archive:FILE   tarball from docker save
+  dir:PATH              unpacked filesystem
+  sbom:FILE             existing SBOM, used as is without cataloging
+
+Attestations, drift and registry metadata need an image in a registry:
+attest and vuln vex --attest reject local sources, and check skips those
+checks for them.`,
 
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := registryauth.LoadConfig(authConfigPath)
@@ -34,8 +43,9 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
+	// cobra has already printed the error to stderr; stdout may carry a
+	// machine-readable report
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
 		os.Exit(1)
 	}
 }
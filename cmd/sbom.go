@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var sbomNoCache bool
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom",
+	Short: "Generate, inspect, export and convert SBOMs",
+}
+
+// loadSBOM reads ref as an SBOM file when it exists on disk and generates
+// one for the image ref otherwise.
+func loadSBOM(ctx context.Context, ref string) (*sbom.ResolvedSBOM, error) {
+	if fi, err := os.Stat(ref); err == nil && !fi.IsDir() {
+		return sbom.LoadFile(ref)
+	}
+	return sbom.ExtractSBOMWithOptions(ctx, ref, sbom.ExtractOptions{NoCache: sbomNoCache})
+}
+
+// encodeSBOM writes doc for a FORMAT[=FILE] target, the syntax syft's -o uses.
+func encodeSBOM(s *sbom.ResolvedSBOM, target string) error {
+	format, path, _ := strings.Cut(target, "=")
+	if err := sbom.ValidateFormat(format); err != nil {
+		return err
+	}
+	doc, err := s.Document()
+	if err != nil {
+		return err
+	}
+	return writeOutput(path, func(w io.Writer) error {
+		return sbom.Encode(w, doc, format)
+	})
+}
+
+func init() {
+	sbomCmd.PersistentFlags().BoolVar(&sbomNoCache, "no-cache", false, "Regenerate image SBOMs instead of reusing cached ones")
+
+	rootCmd.AddCommand(sbomCmd)
+}
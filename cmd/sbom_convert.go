@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var sbomConvertCmd = &cobra.Command{
+	Use:   "convert IN OUT-FORMAT[=FILE]",
+	Short: "Convert an SBOM file to another format",
+	Long: `Read IN in any format Syft can decode (CycloneDX, SPDX or Syft JSON, ...)
+and write it as OUT-FORMAT, to FILE when given and stdout otherwise.`,
+	Example: `  provavalidator sbom convert app.cdx.json spdx-json=app.spdx.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := sbom.LoadFile(args[0])
+		if err != nil {
+			return err
+		}
+		return encodeSBOM(s, args[1])
+	},
+}
+
+func init() {
+	sbomCmd.AddCommand(sbomConvertCmd)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sbomDiffFormat string
+	sbomDiffOutput string
+)
+
+var sbomDiffCmd = &cobra.Command{
+	Use:   "diff A B",
+	Short: "Show packages added, removed or changed between two SBOMs",
+	Long: `Compare the packages of A and B, each an image reference or an SBOM file.
+
+Packages are matched by PURL (ignoring version and qualifiers) or by name and
+type, and versions are ordered with each ecosystem's rules, so a base image
+bump shows up as upgrades rather than removals plus additions.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := loadSBOM(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		b, err := loadSBOM(cmd.Context(), args[1])
+		if err != nil {
+			return err
+		}
+		d := sbom.DiffPackages(a.Packages, b.Packages)
+
+		return writeOutput(sbomDiffOutput, func(w io.Writer) error {
+			switch strings.ToLower(sbomDiffFormat) {
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					Before string `json:"before"`
+					After  string `json:"after"`
+					sbom.Diff
+				}{args[0], args[1], d})
+			case "text", "":
+				writeSBOMDiff(w, args[0], args[1], d)
+				return nil
+			default:
+				return fmt.Errorf("unsupported format %q (use text or json)", sbomDiffFormat)
+			}
+		})
+	},
+}
+
+func writeSBOMDiff(w io.Writer, before, after string, d sbom.Diff) {
+	fmt.Fprintf(w, "Package changes from %s to %s:\n", before, after)
+	fmt.Fprintf(w, "  Added:      %d\n", len(d.Added))
+	fmt.Fprintf(w, "  Removed:    %d\n", len(d.Removed))
+	fmt.Fprintf(w, "  Upgraded:   %d\n", len(d.Upgraded))
+	fmt.Fprintf(w, "  Downgraded: %d\n", len(d.Downgraded))
+	fmt.Fprintf(w, "  Licenses:   %d\n", len(d.LicenseChanged))
+	fmt.Fprintf(w, "  Unchanged:  %d\n", d.Unchanged)
+
+	if len(d.Added) > 0 {
+		fmt.Fprintf(w, "\nAdded:\n")
+		for _, p := range d.Added {
+			fmt.Fprintf(w, "  + %s %s (%s)\n", p.Name, p.Version, p.Type)
+		}
+	}
+	if len(d.Removed) > 0 {
+		fmt.Fprintf(w, "\nRemoved:\n")
+		for _, p := range d.Removed {
+			fmt.Fprintf(w, "  - %s %s (%s)\n", p.Name, p.Version, p.Type)
+		}
+	}
+	if len(d.Upgraded) > 0 {
+		fmt.Fprintf(w, "\nUpgraded:\n")
+		for _, c := range d.Upgraded {
+			fmt.Fprintf(w, "  ^ %s %s -> %s (%s)\n", c.Name, c.Before, c.After, c.Type)
+		}
+	}
+	if len(d.Downgraded) > 0 {
+		fmt.Fprintf(w, "\nDowngraded:\n")
+		for _, c := range d.Downgraded {
+			fmt.Fprintf(w, "  v %s %s -> %s (%s)\n", c.Name, c.Before, c.After, c.Type)
+		}
+	}
+	if len(d.LicenseChanged) > 0 {
+		fmt.Fprintf(w, "\nLicense changes:\n")
+		for _, c := range d.LicenseChanged {
+			fmt.Fprintf(w, "  ~ %s %s: %s -> %s\n", c.Name, c.Version, licenseList(c.Before), licenseList(c.After))
+		}
+	}
+}
+
+func licenseList(ls []string) string {
+	if len(ls) == 0 {
+		return "(none)"
+	}
+	return strings.Join(ls, ", ")
+}
+
+func init() {
+	sbomDiffCmd.Flags().StringVar(&sbomDiffFormat, "format", "text", "Output format (text|json)")
+	sbomDiffCmd.Flags().StringVarP(&sbomDiffOutput, "output", "o", "", "Write to this file instead of stdout")
+
+	sbomCmd.AddCommand(sbomDiffCmd)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var sbomExportCmd = &cobra.Command{
+	Use:   "export IMAGE|FILE FORMAT[=FILE]",
+	Short: "Write the SBOM of an image or file in another format",
+	Long: `Generate the SBOM for IMAGE, or read FILE, and write it as FORMAT, to FILE
+when given and stdout otherwise. Unlike convert, the source may be an image.`,
+	Example: `  provavalidator sbom export alpine:3.19 cyclonedx-json=alpine.cdx.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// reject a bad format before generating an SBOM for nothing
+		format, _, _ := strings.Cut(args[1], "=")
+		if err := sbom.ValidateFormat(format); err != nil {
+			return err
+		}
+		s, err := loadSBOM(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		return encodeSBOM(s, args[1])
+	},
+}
+
+func init() {
+	sbomCmd.AddCommand(sbomExportCmd)
+}
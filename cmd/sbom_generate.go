@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var sbomGenerateOutput string
+
+var sbomGenerateCmd = &cobra.Command{
+	Use:   "generate IMAGE",
+	Short: "Generate an SBOM for an image",
+	Long: `Catalog IMAGE with Syft and write the SBOM.
+
+-o takes FORMAT or FORMAT=FILE, like the syft CLI.`,
+	Example: `  provavalidator sbom generate alpine:3.19 -o spdx-json=alpine.spdx.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := sbom.ExtractSBOMWithOptions(cmd.Context(), args[0], sbom.ExtractOptions{NoCache: sbomNoCache})
+		if err != nil {
+			return err
+		}
+		if _, err := s.Document(); err != nil {
+			// cached SBOMs keep only the encoded payload; when it cannot be
+			// decoded, catalog the image again
+			if s, err = sbom.ExtractSBOMWithOptions(cmd.Context(), args[0], sbom.ExtractOptions{NoCache: true}); err != nil {
+				return err
+			}
+		}
+		return encodeSBOM(s, sbomGenerateOutput)
+	},
+}
+
+func init() {
+	sbomGenerateCmd.Flags().StringVarP(&sbomGenerateOutput, "output", "o", sbom.FormatCycloneDXJSON,
+		fmt.Sprintf("Output format, optionally =FILE (%s)", strings.Join(sbom.EncodeFormats(), "|")))
+
+	sbomCmd.AddCommand(sbomGenerateCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphFormat   string
+	graphOutput   string
+	graphEvidence bool
+)
+
+var sbomGraphCmd = &cobra.Command{
+	Use:   "graph IMAGE|FILE",
+	Short: "Print the package dependency graph of an SBOM",
+	Long: `Print the packages of an SBOM and the relationships between them:
+depends-on, contains (a package bundled in another) and, with --evidence,
+evident-by (the files a package was cataloged from).
+
+DOT output can be rendered with Graphviz, e.g. dot -Tsvg.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := loadSBOM(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		rels := make([]sbom.Relationship, 0, len(s.Relationships))
+		for _, r := range s.Relationships {
+			if r.Type != sbom.EvidentBy || graphEvidence {
+				rels = append(rels, r)
+			}
+		}
+		g := sbom.NewGraph(s.Packages, rels)
+
+		return writeOutput(graphOutput, func(w io.Writer) error {
+			switch strings.ToLower(graphFormat) {
+			case "dot", "":
+				return g.WriteDOT(w)
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(g)
+			default:
+				return fmt.Errorf("unsupported format %q (use dot or json)", graphFormat)
+			}
+		})
+	},
+}
+
+func init() {
+	sbomGraphCmd.Flags().StringVar(&graphFormat, "format", "dot", "Output format (dot|json)")
+	sbomGraphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Write to this file instead of stdout")
+	sbomGraphCmd.Flags().BoolVar(&graphEvidence, "evidence", false, "Include evident-by edges to the files packages were found by")
+
+	sbomCmd.AddCommand(sbomGraphCmd)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var (
+	qualityFormat   string
+	qualityOutput   string
+	qualityMinScore float64
+	qualityNTIA     bool
+)
+
+var sbomQualityCmd = &cobra.Command{
+	Use:   "quality IMAGE|FILE",
+	Short: "Score an SBOM against the NTIA minimum elements",
+	Long: `Report how many packages of an SBOM carry each NTIA minimum element
+(supplier, name, version, unique identifier, dependency relationships) and
+whether the document names its author and creation time, plus license and
+hash coverage.
+
+The score is the mean coverage of all fields, 0-100. With --min-score or
+--require-ntia the command fails when the SBOM falls short, after writing
+the report, so it can gate SBOMs received from vendors.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if qualityMinScore < 0 || qualityMinScore > 100 {
+			return fmt.Errorf("--min-score must be between 0 and 100, got %g", qualityMinScore)
+		}
+		s, err := loadSBOM(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		q := sbom.ScoreQuality(s)
+
+		return writeOutput(qualityOutput, func(w io.Writer) error {
+			switch strings.ToLower(qualityFormat) {
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(struct {
+					SBOM string `json:"sbom"`
+					sbom.Quality
+				}{args[0], q}); err != nil {
+					return err
+				}
+			case "text", "":
+				if err := writeQuality(w, args[0], q); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unsupported format %q (use text or json)", qualityFormat)
+			}
+			return qualityGate(q, qualityMinScore, qualityNTIA)
+		})
+	},
+}
+
+// qualityGate fails when q scores below minScore, or is not NTIA compliant
+// and requireNTIA is set.
+func qualityGate(q sbom.Quality, minScore float64, requireNTIA bool) error {
+	if q.Score < minScore {
+		return fmt.Errorf("SBOM quality score %.1f is below the minimum of %g", q.Score, minScore)
+	}
+	if requireNTIA && !q.NTIA {
+		var missing []string
+		for _, f := range q.Missing() {
+			if f.NTIA {
+				missing = append(missing, f.Field)
+			}
+		}
+		return fmt.Errorf("SBOM does not meet the NTIA minimum elements: incomplete %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func writeQuality(w io.Writer, ref string, q sbom.Quality) error {
+	fmt.Fprintf(w, "SBOM quality of %s (%s, %d packages)\n", ref, q.Format, q.Packages)
+	if len(q.Authors) > 0 {
+		fmt.Fprintf(w, "  Authors: %s\n", strings.Join(q.Authors, ", "))
+	}
+	if q.Created != nil {
+		fmt.Fprintf(w, "  Created: %s\n", q.Created.Format(time.RFC3339))
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tNTIA\tCOVERED\tCOVERAGE")
+	for _, f := range q.Fields {
+		ntia := ""
+		if f.NTIA {
+			ntia = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%.1f%%\n", f.Field, ntia, f.Covered, f.Total, f.Coverage)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nScore: %.1f/100\n", q.Score)
+	fmt.Fprintf(w, "NTIA:  %.1f/100", q.NTIAScore)
+	if q.NTIA {
+		fmt.Fprintln(w, " (minimum elements met)")
+	} else {
+		fmt.Fprintln(w, " (minimum elements not met)")
+	}
+	return nil
+}
+
+func init() {
+	sbomQualityCmd.Flags().StringVar(&qualityFormat, "format", "text", "Output format (text|json)")
+	sbomQualityCmd.Flags().StringVarP(&qualityOutput, "output", "o", "", "Write to this file instead of stdout")
+	sbomQualityCmd.Flags().Float64Var(&qualityMinScore, "min-score", 0, "Fail when the quality score (0-100) is below this")
+	sbomQualityCmd.Flags().BoolVar(&qualityNTIA, "require-ntia", false, "Fail unless every NTIA minimum element is fully covered")
+
+	sbomCmd.AddCommand(sbomQualityCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+var (
+	showTypes   []string
+	showName    string
+	showLicense string
+	showFormat  string
+	showOutput  string
+)
+
+var sbomShowCmd = &cobra.Command{
+	Use:   "show IMAGE|FILE",
+	Short: "List the packages in an SBOM",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := loadSBOM(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		pkgs := sbom.FilterPackages(s.Packages, sbom.PackageFilter{
+			Types:   showTypes,
+			Name:    showName,
+			License: showLicense,
+		})
+
+		return writeOutput(showOutput, func(w io.Writer) error {
+			switch strings.ToLower(showFormat) {
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(pkgs)
+			case "table", "":
+				return writePackageTable(w, pkgs)
+			default:
+				return fmt.Errorf("unsupported format %q (use table or json)", showFormat)
+			}
+		})
+	},
+}
+
+func writePackageTable(w io.Writer, pkgs []sbom.NormalizedPackage) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSION\tTYPE\tLICENSES")
+	for _, p := range pkgs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Name, p.Version, p.Type, strings.Join(p.Licences, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d packages\n", len(pkgs))
+	return err
+}
+
+func init() {
+	sbomShowCmd.Flags().StringSliceVar(&showTypes, "type", nil, "Only packages of these types (deb, apk, npm, go-module, ...)")
+	sbomShowCmd.Flags().StringVar(&showName, "name", "", "Only packages whose name contains this (globs allowed)")
+	sbomShowCmd.Flags().StringVar(&showLicense, "license", "", "Only packages with a license containing this")
+	sbomShowCmd.Flags().StringVar(&showFormat, "format", "table", "Output format (table|json)")
+	sbomShowCmd.Flags().StringVarP(&showOutput, "output", "o", "", "Write to this file instead of stdout")
+
+	sbomCmd.AddCommand(sbomShowCmd)
+}
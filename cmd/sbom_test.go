@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+)
+
+func TestSBOMExport(t *testing.T) {
+	ref, _ := offlineFixture(t)
+	in := strings.TrimPrefix(ref, "sbom:")
+	out := filepath.Join(t.TempDir(), "out.spdx.json")
+
+	runStdout(t, "sbom", "export", in, "spdx-json="+out)
+
+	s, err := sbom.LoadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SPDX adds a package for the document root; jinja2 must survive
+	for _, p := range s.Packages {
+		if p.Name == "jinja2" && p.Version == "3.1.2" {
+			return
+		}
+	}
+	t.Fatalf("jinja2 missing from export: %+v", s.Packages)
+}
@@ -1,17 +1,42 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
+	"github.com/kiptoonkipkurui/provavalidator/pkg/report"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sarif"
 	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
 	"github.com/spf13/cobra"
 )
 
 var (
-	failOn     string
-	format     string
-	ignoreFile string
+	failOn         string
+	format         string
+	ignoreFile     string
+	severitySource string
+	onlyFixed      bool
+	offline        bool
+	dbPath         string
+	minCoverage    float64
+	noCache        bool
+	cacheTTL       time.Duration
+	outputPath     string
+	failOnScore    float64
+	maxCritical    int
+	maxHigh        int
+	maxMedium      int
+	maxLow         int
+	failOnlyFixed  bool
+	baselinePath   string
+	kevPath        string
+	epssPath       string
+	failOnKEV      bool
+	failOnEPSS     float64
+	sortBy         string
 )
 var vulnCmd = &cobra.Command{
 	Use:   "vuln IMAGE",
@@ -21,10 +46,26 @@ var vulnCmd = &cobra.Command{
 		image := args[0]
 		ctx := cmd.Context()
 
-		findings, err := vuln.ScanVulnerabilities(ctx, image)
+		opts, err := scanOptions()
 		if err != nil {
 			return err
 		}
+		policy, err := vulnPolicy()
+		if err != nil {
+			return err
+		}
+		enrichment, err := loadEnrichment()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.ErrOrStderr(), "Scanning vulnerabilities for:", image)
+		res, err := vuln.ScanVulnerabilitiesWithOptions(ctx, image, opts)
+		if err != nil {
+			return err
+		}
+		findings := res.Findings
+		vuln.Enrich(findings, enrichment)
 
 		// Apply ignored rules
 
@@ -34,24 +75,175 @@ var vulnCmd = &cobra.Command{
 			return err
 		}
 		findings = vuln.FilterIgnored(findings, ignored)
+		if onlyFixed {
+			findings = vuln.FilterFixable(findings)
+		}
+		if err := vuln.SortFindings(findings, sortBy); err != nil {
+			return err
+		}
 
-		summary := vuln.Summarize(findings)
+		r := vuln.ScanReport{
+			Image:    image,
+			Summary:  vuln.Summarize(findings),
+			Findings: findings,
+			Coverage: &res.Coverage,
+		}
+		verdict := vuln.Evaluate(findings, &res.Coverage, policy)
+		r.Verdict = &verdict
+		if policy.Baseline != nil {
+			d := vuln.Compare(policy.Baseline, findings)
+			r.Baseline, r.Delta = baselinePath, &d
+		}
 
-		switch strings.ToLower(format) {
-		case "json":
-			return vuln.PrintJSON(image, summary, findings, failOn)
-		case "text", "":
-			return vuln.PrintText(image, summary, findings, failOn)
-		default:
-			return fmt.Errorf("unsupported format %q (use text or json)", format)
+		if err := writeOutput(outputPath, func(w io.Writer) error {
+			return writeScanReport(w, format, r)
+		}); err != nil {
+			return err
 		}
+		return verdict.Err()
 	},
 }
 
+// writeScanReport renders r in any of the vuln output formats.
+func writeScanReport(w io.Writer, format string, r vuln.ScanReport) error {
+	switch strings.ToLower(format) {
+	case "json":
+		return vuln.WriteJSON(w, r)
+	case "sarif":
+		return writeSARIF(w, r)
+	case "text", "":
+		return vuln.WriteText(w, r)
+	}
+
+	renderer, err := report.Lookup(format)
+	if err != nil {
+		return fmt.Errorf("unsupported format %q (use text, json, sarif, junit, markdown or html)", format)
+	}
+	rep := report.New(r.Image)
+	rep.SetScan(r.Findings, r.Coverage)
+	if r.Delta != nil {
+		rep.SetDelta(r.Baseline, *r.Delta)
+	}
+	rep.Add(report.ScanChecks(r.Findings, r.Verdict)...)
+	return renderer.Render(w, rep)
+}
+
+// scanOptions builds the scan options from the flags vuln and its
+// subcommands share.
+func scanOptions() (vuln.ScanOptions, error) {
+	opts := vuln.DefaultScanOptions()
+	precedence, err := vuln.ParseSeverityPrecedence(severitySource)
+	if err != nil {
+		return opts, err
+	}
+	opts.SeverityPrecedence = precedence
+	opts.Offline = offline
+	opts.DBPath = dbPath
+	opts.NoCache = noCache
+	opts.CacheTTL = cacheTTL
+	return opts, nil
+}
+
+// loadEnrichment reads the --kev and --epss files; either may be unset.
+func loadEnrichment() (vuln.Enrichment, error) {
+	var (
+		e   vuln.Enrichment
+		err error
+	)
+	if kevPath != "" {
+		if e.KEV, err = vuln.LoadKEV(kevPath); err != nil {
+			return e, err
+		}
+	}
+	if epssPath != "" {
+		if e.EPSS, err = vuln.LoadEPSS(epssPath); err != nil {
+			return e, err
+		}
+	}
+	if (failOnKEV && e.KEV == nil) || (failOnEPSS > 0 && e.EPSS == nil) {
+		return e, fmt.Errorf("--fail-on-kev needs --kev and --fail-on-epss needs --epss")
+	}
+	return e, nil
+}
+
+// vulnPolicy builds the fail thresholds from the command line flags.
+func vulnPolicy() (vuln.Policy, error) {
+	p := vuln.Policy{
+		FailOnScore: failOnScore,
+		FailOnKEV:   failOnKEV,
+		FailOnEPSS:  failOnEPSS,
+		MinCoverage: minCoverage,
+		OnlyFixable: failOnlyFixed,
+	}
+	if failOn != "" {
+		level, err := vuln.ParseSeverity(failOn)
+		if err != nil {
+			return p, err
+		}
+		p.FailOn = level
+	}
+
+	for sev, max := range map[vuln.Severity]int{
+		vuln.SeverityCritical: maxCritical,
+		vuln.SeverityHigh:     maxHigh,
+		vuln.SeverityMedium:   maxMedium,
+		vuln.SeverityLow:      maxLow,
+	} {
+		if max >= 0 {
+			if p.MaxCounts == nil {
+				p.MaxCounts = map[vuln.Severity]int{}
+			}
+			p.MaxCounts[sev] = max
+		}
+	}
+
+	if baselinePath != "" {
+		baseline, err := vuln.LoadBaseline(baselinePath)
+		if err != nil {
+			return p, err
+		}
+		p.Baseline = baseline
+	}
+	return p, nil
+}
+
+// writeSARIF writes the findings as SARIF with the policy verdict attached.
+func writeSARIF(w io.Writer, r vuln.ScanReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarif.Build(r.Findings, sarif.Options{ImageRef: r.Image, Verdict: r.Verdict, Delta: r.Delta}))
+}
+
+// addPolicyFlags registers the fail threshold and enrichment flags that
+// vulnPolicy and loadEnrichment read, so vuln and check share them.
+func addPolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&failOn, "fail-on", "", "Fail if vulnerabilities of this severity or higher are found (low|medium|high|critical)")
+	cmd.Flags().Float64Var(&failOnScore, "fail-on-score", 0, "Fail if any vulnerability has a CVSS base score at or above this value")
+	cmd.Flags().IntVar(&maxCritical, "max-critical", -1, "Fail if there are more critical vulnerabilities than this (-1 disables)")
+	cmd.Flags().IntVar(&maxHigh, "max-high", -1, "Fail if there are more high vulnerabilities than this (-1 disables)")
+	cmd.Flags().IntVar(&maxMedium, "max-medium", -1, "Fail if there are more medium vulnerabilities than this (-1 disables)")
+	cmd.Flags().IntVar(&maxLow, "max-low", -1, "Fail if there are more low vulnerabilities than this (-1 disables)")
+	cmd.Flags().BoolVar(&failOnlyFixed, "fail-only-fixed", false, "Only vulnerabilities with a fix available count toward the fail thresholds")
+	cmd.Flags().StringVar(&baselinePath, "baseline", "", "Earlier JSON report to compare against; only new vulnerabilities count toward the fail thresholds")
+	cmd.Flags().StringVar(&kevPath, "kev", "", "CISA Known Exploited Vulnerabilities JSON file to mark exploited findings")
+	cmd.Flags().StringVar(&epssPath, "epss", "", "EPSS scores CSV (optionally gzipped) to add exploit probabilities")
+	cmd.Flags().BoolVar(&failOnKEV, "fail-on-kev", false, "Fail on any known exploited vulnerability, whatever its severity (needs --kev)")
+	cmd.Flags().Float64Var(&failOnEPSS, "fail-on-epss", 0, "Fail if any vulnerability has an EPSS probability at or above this value, 0-1 (needs --epss)")
+	cmd.Flags().Float64Var(&minCoverage, "min-coverage", 0, "Fail if less than this percentage of packages could be scanned (0-100)")
+}
+
 func init() {
-	vulnCmd.Flags().StringVar(&failOn, "fail-on", "", "Fail if vulnerabilities of this severity or higher are found (low|medium|high|critical)")
-	vulnCmd.Flags().StringVar(&format, "format", "text", "Output format (text|json)")
+	addPolicyFlags(vulnCmd)
+	vulnCmd.Flags().StringVar(&sortBy, "sort-by", "", "Order findings by severity, cvss, epss, kev, package or id (default: scan order)")
+	vulnCmd.Flags().StringVar(&format, "format", "text", "Output format (text|json|sarif|junit|markdown|html)")
+	vulnCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the report to this file instead of stdout")
 	vulnCmd.Flags().StringVar(&ignoreFile, "ignore-file", "", "Path to vulnerability ignore file")
+	vulnCmd.Flags().BoolVar(&onlyFixed, "only-fixed", false, "Only report vulnerabilities that have a fixed version available")
+	vulnCmd.PersistentFlags().StringVar(&severitySource, "severity-source", "cvss", "Which severity wins when both exist (cvss|vendor)")
+	vulnCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Regenerate the SBOM and query OSV instead of reusing cached results")
+	vulnCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", vuln.DefaultCacheTTL, "How long cached OSV results are reused")
+	vulnCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Match against the local OSV database instead of the OSV API")
+	vulnCmd.PersistentFlags().StringVar(&dbPath, "db", "", "Path to the local OSV database (default: user cache dir)")
 
 	rootCmd.AddCommand(vulnCmd)
 }
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+	"github.com/spf13/cobra"
+)
+
+var dbStatusFormat string
+
+var vulnDBCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the local OSV database used by --offline",
+}
+
+var vulnDBImportCmd = &cobra.Command{
+	Use:   "import FILE.zip...",
+	Short: "Import OSV ecosystem exports (all.zip) into the local database",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := vuln.OpenLocalDB(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		for _, path := range args {
+			n, skipped, err := db.ImportZip(cmd.Context(), path)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Imported %d records from %s\n", n, path)
+			if skipped > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %d malformed records in %s\n", skipped, path)
+			}
+		}
+		return nil
+	},
+}
+
+var vulnDBStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show local OSV database age and record counts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := vuln.OpenLocalDB(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		st, err := db.Status(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		switch strings.ToLower(dbStatusFormat) {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(st)
+		case "text", "":
+			printDBStatus(st)
+			return nil
+		default:
+			return fmt.Errorf("unsupported format %q (use text or json)", dbStatusFormat)
+		}
+	},
+}
+
+func printDBStatus(st *vuln.LocalDBStatus) {
+	fmt.Println("Database:", st.Path)
+	fmt.Println("Records: ", st.Records)
+	if st.LastImport == nil {
+		fmt.Println("Last import: never")
+	} else {
+		age := time.Duration(st.AgeSeconds) * time.Second
+		fmt.Printf("Last import: %s (%s ago)\n", st.LastImport.Format("2006-01-02 15:04:05 MST"), age)
+	}
+	if st.LastModified != "" {
+		fmt.Println("Newest record:", st.LastModified)
+	}
+
+	ecos := make([]string, 0, len(st.Ecosystems))
+	for eco := range st.Ecosystems {
+		ecos = append(ecos, eco)
+	}
+	sort.Strings(ecos)
+	if len(ecos) > 0 {
+		fmt.Println("Ecosystems:")
+	}
+	for _, eco := range ecos {
+		fmt.Printf("  %-24s %d\n", eco, st.Ecosystems[eco])
+	}
+}
+
+func init() {
+	vulnDBStatusCmd.Flags().StringVar(&dbStatusFormat, "format", "text", "Output format (text|json)")
+
+	vulnDBCmd.AddCommand(vulnDBImportCmd, vulnDBStatusCmd)
+	vulnCmd.AddCommand(vulnDBCmd)
+}
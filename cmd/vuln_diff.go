@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat     string
+	diffOutput     string
+	diffFailOn     string
+	diffIgnoreFile string
+	diffOnlyFixed  bool
+)
+
+var vulnDiffCmd = &cobra.Command{
+	Use:   "diff OLD NEW",
+	Short: "Show vulnerabilities introduced and fixed between two images",
+	Long: `Scan OLD and NEW and classify NEW's findings as new, fixed or unchanged.
+Either side may also be a JSON report from "vuln --format json".
+
+Findings are matched by package (ignoring version) and vulnerability ID or
+alias. --fail-on only considers new findings.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		var policy vuln.Policy
+		if diffFailOn != "" {
+			level, err := vuln.ParseSeverity(diffFailOn)
+			if err != nil {
+				return err
+			}
+			policy.FailOn = level
+		}
+
+		ignored, err := vuln.LoadIgnoreFile(diffIgnoreFile)
+		if err != nil {
+			return err
+		}
+
+		old, _, err := diffFindings(ctx, args[0], ignored)
+		if err != nil {
+			return err
+		}
+		current, cov, err := diffFindings(ctx, args[1], ignored)
+		if err != nil {
+			return err
+		}
+
+		policy.Baseline = old
+		verdict := vuln.Evaluate(current, cov, policy)
+		delta := vuln.Compare(old, current)
+
+		r := vuln.ScanReport{
+			Image:    args[1],
+			Summary:  vuln.Summarize(current),
+			Findings: current,
+			Coverage: cov,
+			Verdict:  &verdict,
+			Baseline: args[0],
+			Delta:    &delta,
+		}
+		if err := writeOutput(diffOutput, func(w io.Writer) error {
+			return writeScanReport(w, diffFormat, r)
+		}); err != nil {
+			return err
+		}
+		return verdict.Err()
+	},
+}
+
+// diffFindings reads the findings of a JSON report when ref is a file and
+// scans ref as an image otherwise. Coverage is nil for reports.
+func diffFindings(ctx context.Context, ref string, ignored map[string]struct{}) ([]vuln.Finding, *vuln.Coverage, error) {
+	var (
+		findings []vuln.Finding
+		cov      *vuln.Coverage
+	)
+	if fi, err := os.Stat(ref); err == nil && !fi.IsDir() {
+		if findings, err = vuln.LoadBaseline(ref); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		opts, err := scanOptions()
+		if err != nil {
+			return nil, nil, err
+		}
+		res, err := vuln.ScanVulnerabilitiesWithOptions(ctx, ref, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scan %s: %w", ref, err)
+		}
+		findings, cov = res.Findings, &res.Coverage
+	}
+
+	findings = vuln.FilterIgnored(findings, ignored)
+	if diffOnlyFixed {
+		findings = vuln.FilterFixable(findings)
+	}
+	return findings, cov, nil
+}
+
+func init() {
+	vulnDiffCmd.Flags().StringVar(&diffFormat, "format", "text", "Output format (text|json|sarif|junit|markdown|html)")
+	vulnDiffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "Write the report to this file instead of stdout")
+	vulnDiffCmd.Flags().StringVar(&diffFailOn, "fail-on", "", "Fail if new vulnerabilities of this severity or higher are found (low|medium|high|critical)")
+	vulnDiffCmd.Flags().StringVar(&diffIgnoreFile, "ignore-file", "", "Path to vulnerability ignore file")
+	vulnDiffCmd.Flags().BoolVar(&diffOnlyFixed, "only-fixed", false, "Only compare vulnerabilities that have a fixed version available")
+
+	vulnCmd.AddCommand(vulnDiffCmd)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+const testSBOM = `{
+	"bomFormat": "CycloneDX",
+	"specVersion": "1.4",
+	"components": [
+		{"type": "library", "name": "jinja2", "version": "3.1.2", "purl": "pkg:pypi/jinja2@3.1.2"}
+	]
+}`
+
+const testOSVRecord = `{
+	"id": "PYSEC-2024-1",
+	"modified": "2024-05-01T00:00:00Z",
+	"summary": "jinja2 sandbox escape",
+	"affected": [{
+		"package": {"name": "jinja2", "ecosystem": "PyPI"},
+		"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.1.3"}]}]
+	}]
+}`
+
+// offlineFixture writes an SBOM with one vulnerable package and a local OSV
+// database that knows about it, so commands can scan without a network.
+func offlineFixture(t *testing.T) (sbomRef, db string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	sbomPath := filepath.Join(dir, "sbom.json")
+	if err := os.WriteFile(sbomPath, []byte(testSBOM), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath := filepath.Join(dir, "all.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("PYSEC-2024-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(testOSVRecord)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db = filepath.Join(dir, "osv.db")
+	d, err := vuln.OpenLocalDB(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if _, _, err := d.ImportZip(context.Background(), zipPath); err != nil {
+		t.Fatal(err)
+	}
+	return "sbom:" + sbomPath, db
+}
+
+// runStdout executes the root command with args and returns what it wrote
+// to the process's stdout, which is where reports go.
+func runStdout(t *testing.T, args ...string) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- b
+	}()
+
+	rootCmd.SetArgs(args)
+	rootCmd.SetErr(io.Discard)
+	runErr := rootCmd.Execute()
+	w.Close()
+	b := <-out
+	if runErr != nil {
+		t.Fatalf("%v: %v\n%s", args, runErr, b)
+	}
+	return b
+}
+
+func TestVulnStdoutIsMachineReadable(t *testing.T) {
+	ref, db := offlineFixture(t)
+
+	t.Run("json", func(t *testing.T) {
+		b := runStdout(t, "vuln", ref, "--offline", "--db", db, "--format", "json")
+		var r vuln.ScanReport
+		if err := json.Unmarshal(b, &r); err != nil {
+			t.Fatalf("stdout is not JSON: %v\n%s", err, b)
+		}
+		if len(r.Findings) != 1 || r.Findings[0].VulnID != "PYSEC-2024-1" {
+			t.Fatalf("unexpected findings: %+v", r.Findings)
+		}
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		b := runStdout(t, "vuln", ref, "--offline", "--db", db, "--format", "sarif")
+		var log struct {
+			Version string `json:"version"`
+			Runs    []struct {
+				Results []struct {
+					RuleID string `json:"ruleId"`
+				} `json:"results"`
+			} `json:"runs"`
+		}
+		if err := json.Unmarshal(b, &log); err != nil {
+			t.Fatalf("stdout is not SARIF: %v\n%s", err, b)
+		}
+		if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+			t.Fatalf("unexpected SARIF: %s", b)
+		}
+	})
+}
+
+func TestVulnDiffOffline(t *testing.T) {
+	ref, db := offlineFixture(t)
+
+	// without --offline this would query the OSV API, which the sandbox
+	// cannot reach
+	b := runStdout(t, "vuln", "diff", ref, ref, "--offline", "--db", db, "--format", "json")
+	var r vuln.ScanReport
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, b)
+	}
+	if len(r.Findings) != 1 || r.Delta == nil || len(r.Delta.New) != 0 {
+		t.Fatalf("unexpected diff: %s", b)
+	}
+}
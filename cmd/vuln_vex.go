@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/attestation"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/registry"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vex"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vexIgnoreFile string
+	vexAuthor     string
+	vexOutput     string
+	vexAttest     bool
+	vexKey        string
+)
+
+var vulnVexCmd = &cobra.Command{
+	Use:   "vex IMAGE",
+	Short: "Emit an OpenVEX document from scan findings and triage decisions",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		image := args[0]
+		ctx := cmd.Context()
+
+		local := sbom.IsLocalSource(image)
+		if local && vexAttest {
+			return fmt.Errorf("--attest needs an image in a registry, not %s", image)
+		}
+
+		var digest string
+		if !local {
+			d, err := registry.ResolveDigest(ctx, image)
+			if err != nil {
+				return err
+			}
+			digest = d
+		}
+
+		opts, err := scanOptions()
+		if err != nil {
+			return err
+		}
+		res, err := vuln.ScanVulnerabilitiesWithOptions(ctx, image, opts)
+		if err != nil {
+			return err
+		}
+		findings := res.Findings
+
+		productRef := image
+		if local {
+			// statements are bound to the digest the image archive or
+			// layout records; there is no repository to name
+			digest, productRef = res.SBOM.ImageDigest, ""
+			if digest == "" {
+				return fmt.Errorf("vex: %s records no image digest to bind statements to", image)
+			}
+		}
+
+		ignore, err := vuln.ReadIgnoreFile(vexIgnoreFile)
+		if err != nil {
+			return err
+		}
+
+		doc, err := vex.Build(findings, ignore, vex.Options{
+			ImageRef: productRef,
+			Digest:   digest,
+			Author:   vexAuthor,
+		})
+		if err != nil {
+			return err
+		}
+
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal vex document: %w", err)
+		}
+
+		if vexOutput == "" || vexOutput == "-" {
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+		} else if err := os.WriteFile(vexOutput, append(b, '\n'), 0o644); err != nil {
+			return fmt.Errorf("write vex document: %w", err)
+		}
+
+		if vexAttest {
+			// attest the digest, not the tag we were given
+			ref := image
+			if r, err := name.ParseReference(image); err == nil {
+				ref = r.Context().Name() + "@" + digest
+			}
+			if err := attestation.AttachPredicate(ctx, ref, b, vex.PredicateType, appCtx.AuthConfig, attestation.AttachOptions{
+				KeyRef:  vexKey,
+				Replace: true,
+			}); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.ErrOrStderr(), "VEX attestation attached to", ref)
+		}
+		return nil
+	},
+}
+
+func init() {
+	vulnVexCmd.Flags().StringVar(&vexIgnoreFile, "ignore-file", "", "Path to vulnerability ignore file; ignored entries become not_affected statements")
+	vulnVexCmd.Flags().StringVar(&vexAuthor, "author", "", "Author recorded in the VEX document")
+	vulnVexCmd.Flags().StringVarP(&vexOutput, "output", "o", "", "Write the VEX document to this file instead of stdout")
+	vulnVexCmd.Flags().BoolVar(&vexAttest, "attest", false, "Sign the VEX document and attach it to the image as an in-toto attestation")
+	vulnVexCmd.Flags().StringVar(&vexKey, "key", "", "Cosign key reference used with --attest (keyless when empty)")
+
+	vulnCmd.AddCommand(vulnVexCmd)
+}
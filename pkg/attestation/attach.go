@@ -0,0 +1,95 @@
+package attestation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/registryauth"
+	"github.com/sigstore/cosign/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/cmd/cosign/cli/options"
+)
+
+// AttachOptions control how a predicate is signed and pushed.
+type AttachOptions struct {
+	// KeyRef is a cosign key reference (file path, KMS URI, ...).
+	// When empty, keyless signing through Fulcio is used.
+	KeyRef string
+
+	// Replace removes existing attestations of the same predicate type.
+	Replace bool
+
+	Timeout time.Duration
+}
+
+// AttachPredicate wraps predicate in an in-toto statement for the image
+// digest, signs it and pushes it to the registry next to the image, the
+// same way `cosign attest --type <predicateType>` does.
+func AttachPredicate(ctx context.Context, imageRef string, predicate []byte, predicateType string, authCfg *registryauth.Config, opts AttachOptions) error {
+	keychain, _, err := registryauth.KeyChainForImage(authCfg, imageRef)
+	if err != nil {
+		return fmt.Errorf("image keychain error : %w", err)
+	}
+
+	// cosign reads the predicate from a path
+	f, err := os.CreateTemp("", "provavalidator-predicate-*.json")
+	if err != nil {
+		return fmt.Errorf("attach: create predicate file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(predicate); err != nil {
+		f.Close()
+		return fmt.Errorf("attach: write predicate file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("attach: write predicate file: %w", err)
+	}
+
+	ko := options.KeyOpts{
+		KeyRef:    opts.KeyRef,
+		PassFunc:  generate.GetPass,
+		FulcioURL: options.DefaultFulcioURL,
+		RekorURL:  options.DefaultRekorURL,
+
+		SkipConfirmation: true,
+	}
+	if opts.KeyRef == "" {
+		// keyless signing is experimental in cosign v1 and gated by this env var
+		restore, err := setenv("COSIGN_EXPERIMENTAL", "1")
+		if err != nil {
+			return fmt.Errorf("attach: enable keyless signing: %w", err)
+		}
+		defer restore()
+	}
+
+	regOpts := options.RegistryOptions{Keychain: keychain}
+
+	if err := attest.AttestCmd(ctx, ko, regOpts, imageRef, "", "", false, f.Name(), false, predicateType, opts.Replace, opts.Timeout, false); err != nil {
+		return fmt.Errorf("attach attestation: %w", err)
+	}
+	return nil
+}
+
+// setenv sets key for the duration of a call and returns a func that puts
+// back the previous value, so the library does not leave the process
+// environment changed.
+func setenv(key, value string) (func(), error) {
+	prev, had := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		return nil, err
+	}
+	return func() {
+		var err error
+		if had {
+			err = os.Setenv(key, prev)
+		} else {
+			err = os.Unsetenv(key)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "attach: restore %s: %v\n", key, err)
+		}
+	}, nil
+}
@@ -0,0 +1,32 @@
+package attestation
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSetenvRestores(t *testing.T) {
+	const key = "PROVAVALIDATOR_TEST_SETENV"
+
+	restore, err := setenv(key, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(key); got != "1" {
+		t.Fatalf("%s = %q during call", key, got)
+	}
+	restore()
+	if _, ok := os.LookupEnv(key); ok {
+		t.Fatalf("%s left set", key)
+	}
+
+	t.Setenv(key, "before")
+	restore, err = setenv(key, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restore()
+	if got := os.Getenv(key); got != "before" {
+		t.Fatalf("%s = %q, want previous value", key, got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"os"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -23,7 +24,7 @@ func verifyWithCosign(ctx context.Context, ref name.Reference, cfg *registryauth
 	repo := ref.Context()
 	auth, _ := keychain.Resolve(repo)
 
-	fmt.Printf("Using registry auth for %s: %T\n",
+	fmt.Fprintf(os.Stderr, "Using registry auth for %s: %T\n",
 		ref.Context().RegistryStr(), auth)
 	// Load Fulcio roots
 
@@ -31,20 +32,9 @@ func verifyWithCosign(ctx context.Context, ref name.Reference, cfg *registryauth
 		return nil, fmt.Errorf("image keychain error : %w", err)
 	}
 
-	// Load Fulcio root certificates (this is what cosign CLI does implicitly)
-	roots, err := fulcio.GetRoots()
+	opts, err := cosignCheckOpts()
 	if err != nil {
-		return nil, fmt.Errorf("load fulcio roots: %w", err)
-	}
-	opts := &cosign.CheckOpts{
-		RootCerts: roots,
-		// Rekor + SCT verification are enabled by default
-		// Use Docker / GHCR credentials from ~/.docker/config.json
-		RegistryClientOpts: []ociremote.Option{
-			ociremote.WithRemoteOptions(
-				remote.WithAuthFromKeychain(authn.DefaultKeychain),
-			),
-		},
+		return nil, err
 	}
 
 	checked, _, err := cosign.VerifyImageAttestations(ctx, ref, opts)
@@ -71,6 +61,25 @@ func verifyWithCosign(ctx context.Context, ref name.Reference, cfg *registryauth
 	return results, nil
 }
 
+// cosignCheckOpts is what the cosign CLI verifies against by default.
+func cosignCheckOpts() (*cosign.CheckOpts, error) {
+	// Load Fulcio root certificates (this is what cosign CLI does implicitly)
+	roots, err := fulcio.GetRoots()
+	if err != nil {
+		return nil, fmt.Errorf("load fulcio roots: %w", err)
+	}
+	return &cosign.CheckOpts{
+		RootCerts: roots,
+		// Rekor + SCT verification are enabled by default
+		// Use Docker / GHCR credentials from ~/.docker/config.json
+		RegistryClientOpts: []ociremote.Option{
+			ociremote.WithRemoteOptions(
+				remote.WithAuthFromKeychain(authn.DefaultKeychain),
+			),
+		},
+	}, nil
+}
+
 func certSubjectIssuer(cert *x509.Certificate) (subject, issuer string) {
 	if cert == nil {
 		return "", ""
@@ -0,0 +1,69 @@
+package attestation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func envelope(t *testing.T, predicateType string, predicate any) []byte {
+	t.Helper()
+	st, err := json.Marshal(map[string]any{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": predicateType,
+		"predicate":     predicate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(map[string]string{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString(st),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSBOMPredicate(t *testing.T) {
+	cdx := map[string]any{"bomFormat": "CycloneDX", "specVersion": "1.4"}
+
+	tests := []struct {
+		name       string
+		env        []byte
+		wantFormat string
+		wantDoc    string
+	}{
+		{"cosign v1 wrapped", envelope(t, PredicateCycloneDX, map[string]any{"Data": cdx}), "cyclonedx", `{"bomFormat":"CycloneDX","specVersion":"1.4"}`},
+		{"bare predicate", envelope(t, PredicateCycloneDX, cdx), "cyclonedx", `{"bomFormat":"CycloneDX","specVersion":"1.4"}`},
+		{"spdx tag-value", envelope(t, PredicateSPDX, map[string]any{"Data": "SPDXVersion: SPDX-2.3\n"}), "spdx", "SPDXVersion: SPDX-2.3\n"},
+		{"not an sbom", envelope(t, "https://slsa.dev/provenance/v0.2", map[string]any{}), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, format, err := sbomPredicate(tt.env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			if tt.wantDoc == "" {
+				if doc != nil {
+					t.Errorf("expected no document, got %s", doc)
+				}
+				return
+			}
+			var got, want any
+			if json.Unmarshal(doc, &got) == nil && json.Unmarshal([]byte(tt.wantDoc), &want) == nil {
+				gb, _ := json.Marshal(got)
+				wb, _ := json.Marshal(want)
+				doc, tt.wantDoc = gb, string(wb)
+			}
+			if string(doc) != tt.wantDoc {
+				t.Errorf("document = %s, want %s", doc, tt.wantDoc)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package cvss parses CVSS vector strings and computes base scores.
+//
+// Supported: CVSS v2.0, v3.0, v3.1 and v4.0. OSV reports severity as a
+// vector string (e.g. "CVSS:3.1/AV:N/AC:L/..."), so this is what turns
+// most findings into a usable number.
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type Version string
+
+const (
+	V2  Version = "2.0"
+	V30 Version = "3.0"
+	V31 Version = "3.1"
+	V40 Version = "4.0"
+)
+
+// DefaultPreference orders versions when a vulnerability carries more than
+// one vector: newest specification first.
+var DefaultPreference = []Version{V40, V31, V30, V2}
+
+// Score is a parsed vector and its computed base score.
+type Score struct {
+	Version Version
+	Vector  string
+	Base    float64
+}
+
+// Parse detects the vector's version and computes its base score.
+// v2 vectors have no prefix ("AV:N/AC:L/Au:N/C:P/I:P/A:P").
+func Parse(vector string) (Score, error) {
+	v := strings.TrimSpace(vector)
+	if v == "" {
+		return Score{}, fmt.Errorf("cvss: empty vector")
+	}
+
+	var (
+		ver  Version
+		base float64
+		err  error
+	)
+	switch {
+	case strings.HasPrefix(v, "CVSS:4.0/"):
+		ver = V40
+		base, err = scoreV4(strings.TrimPrefix(v, "CVSS:4.0/"))
+	case strings.HasPrefix(v, "CVSS:3.1/"):
+		ver = V31
+		base, err = scoreV3(strings.TrimPrefix(v, "CVSS:3.1/"), roundUpV31)
+	case strings.HasPrefix(v, "CVSS:3.0/"):
+		ver = V30
+		base, err = scoreV3(strings.TrimPrefix(v, "CVSS:3.0/"), roundUpV30)
+	case strings.HasPrefix(v, "CVSS:2.0/"):
+		ver = V2
+		base, err = scoreV2(strings.TrimPrefix(v, "CVSS:2.0/"))
+	case strings.HasPrefix(v, "CVSS:"):
+		return Score{}, fmt.Errorf("cvss: unsupported version in %q", v)
+	default:
+		// Some feeds wrap v2 vectors in parentheses: (AV:N/AC:L/...)
+		ver = V2
+		base, err = scoreV2(strings.Trim(v, "()"))
+	}
+	if err != nil {
+		return Score{}, fmt.Errorf("cvss: %s vector %q: %w", ver, v, err)
+	}
+
+	return Score{Version: ver, Vector: v, Base: base}, nil
+}
+
+// Rank returns the position of v in pref (lower is preferred).
+// Versions not listed rank last.
+func Rank(v Version, pref []Version) int {
+	for i, p := range pref {
+		if p == v {
+			return i
+		}
+	}
+	return len(pref)
+}
+
+// parseMetrics splits "K:V/K:V" into a map, rejecting malformed parts and
+// duplicates. Values are validated by the version-specific scorer.
+func parseMetrics(s string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, part := range strings.Split(s, "/") {
+		k, val, ok := strings.Cut(part, ":")
+		if !ok || k == "" || val == "" {
+			return nil, fmt.Errorf("malformed metric %q", part)
+		}
+		if _, dup := out[k]; dup {
+			return nil, fmt.Errorf("duplicate metric %q", k)
+		}
+		out[k] = val
+	}
+	return out, nil
+}
+
+// weights looks up every required metric in m and returns its weight.
+func weights(m map[string]string, table map[string]map[string]float64, required ...string) (map[string]float64, error) {
+	out := make(map[string]float64, len(required))
+	for _, k := range required {
+		val, ok := m[k]
+		if !ok {
+			return nil, fmt.Errorf("missing metric %s", k)
+		}
+		w, ok := table[k][val]
+		if !ok {
+			return nil, fmt.Errorf("invalid value %s:%s", k, val)
+		}
+		out[k] = w
+	}
+	return out, nil
+}
+
+// round1 rounds half up to one decimal like the v4.0 reference calculator,
+// absorbing float noise (e.g. 4.65 computed as 4.6499999999999995).
+func round1(f float64) float64 {
+	return math.Round(f*10+1e-9) / 10
+}
@@ -0,0 +1,69 @@
+package cvss
+
+import "testing"
+
+func TestParse_ReferenceScores(t *testing.T) {
+	tests := []struct {
+		vector  string
+		version Version
+		want    float64
+	}{
+		// v2 (NVD examples)
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P", V2, 7.5},
+		{"AV:N/AC:L/Au:N/C:C/I:C/A:C", V2, 10.0},
+		{"AV:N/AC:M/Au:N/C:P/I:N/A:N", V2, 4.3},
+		{"AV:L/AC:L/Au:N/C:C/I:C/A:C", V2, 7.2},
+		{"(AV:N/AC:L/Au:N/C:N/I:N/A:P)", V2, 5.0},
+		{"AV:N/AC:L/Au:N/C:N/I:N/A:N", V2, 0},
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P/E:F/RL:OF/RC:C", V2, 7.5},
+
+		// v3.0 / v3.1
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", V31, 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", V31, 10.0},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", V31, 6.1},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N", V31, 5.5},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", V31, 5.9},
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", V31, 1.6},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", V31, 0},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", V30, 9.8},
+		{"CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", V30, 6.4},
+
+		// v4.0 (FIRST calculator)
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", V40, 9.3},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", V40, 10.0},
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", V40, 8.5},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:A/VC:L/VI:L/VA:N/SC:N/SI:N/SA:N", V40, 5.1},
+		{"CVSS:4.0/AV:P/AC:H/AT:P/PR:H/UI:A/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N", V40, 1.0},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U", V40, 8.1},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N", V40, 0},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.vector)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.vector, err)
+		}
+		if got.Version != tt.version {
+			t.Errorf("%s: version = %s, want %s", tt.vector, got.Version, tt.version)
+		}
+		if got.Base != tt.want {
+			t.Errorf("%s: base = %.1f, want %.1f", tt.vector, got.Base, tt.want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, v := range []string{
+		"",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",         // missing A
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",     // bad value
+		"CVSS:3.1/AV:N/AV:N/PR:N/UI:N/S:U/C:H/I:H/A:H",     // duplicate
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H", // missing subsequent system
+		"CVSS:5.0/AV:N",
+		"7.5",
+	} {
+		if _, err := Parse(v); err == nil {
+			t.Errorf("%q: expected error", v)
+		}
+	}
+}
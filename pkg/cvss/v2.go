@@ -0,0 +1,33 @@
+package cvss
+
+// CVSS v2.0 base equation.
+// Spec: https://www.first.org/cvss/v2/guide (section 3.2.1)
+var v2Weights = map[string]map[string]float64{
+	"AV": {"L": 0.395, "A": 0.646, "N": 1.0},
+	"AC": {"H": 0.35, "M": 0.61, "L": 0.71},
+	"Au": {"M": 0.45, "S": 0.56, "N": 0.704},
+	"C":  {"N": 0, "P": 0.275, "C": 0.660},
+	"I":  {"N": 0, "P": 0.275, "C": 0.660},
+	"A":  {"N": 0, "P": 0.275, "C": 0.660},
+}
+
+func scoreV2(vector string) (float64, error) {
+	m, err := parseMetrics(vector)
+	if err != nil {
+		return 0, err
+	}
+	// temporal/environmental metrics may follow; only the base group is scored
+	w, err := weights(m, v2Weights, "AV", "AC", "Au", "C", "I", "A")
+	if err != nil {
+		return 0, err
+	}
+
+	impact := 10.41 * (1 - (1-w["C"])*(1-w["I"])*(1-w["A"]))
+	exploitability := 20 * w["AV"] * w["AC"] * w["Au"]
+
+	f := 1.176
+	if impact == 0 {
+		f = 0
+	}
+	return round1(((0.6 * impact) + (0.4 * exploitability) - 1.5) * f), nil
+}
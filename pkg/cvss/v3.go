@@ -0,0 +1,68 @@
+package cvss
+
+import "math"
+
+// CVSS v3.x base equation.
+// Spec: https://www.first.org/cvss/v3.1/specification-document (section 7.1)
+var v3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27}, // scope unchanged
+	"UI": {"N": 0.85, "R": 0.62},
+	"S":  {"U": 0, "C": 0},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// privileges required weigh more when scope changes
+var v3PRScopeChanged = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+
+func scoreV3(vector string, roundUp func(float64) float64) (float64, error) {
+	m, err := parseMetrics(vector)
+	if err != nil {
+		return 0, err
+	}
+	w, err := weights(m, v3Weights, "AV", "AC", "PR", "UI", "S", "C", "I", "A")
+	if err != nil {
+		return 0, err
+	}
+
+	changed := m["S"] == "C"
+	pr := w["PR"]
+	if changed {
+		pr = v3PRScopeChanged[m["PR"]]
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	var impact float64
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	exploitability := 8.22 * w["AV"] * w["AC"] * pr * w["UI"]
+
+	if impact <= 0 {
+		return 0, nil
+	}
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUpV30 is the v3.0 "round up to one decimal".
+func roundUpV30(f float64) float64 {
+	return math.Ceil(f*10) / 10
+}
+
+// roundUpV31 is the v3.1 Roundup, which avoids floating point artifacts
+// (Appendix A of the v3.1 specification).
+func roundUpV31(f float64) float64 {
+	i := int64(math.Round(f * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
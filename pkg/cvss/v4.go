@@ -0,0 +1,323 @@
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// CVSS v4.0 scoring follows the FIRST reference calculator: the vector is
+// mapped to a MacroVector (EQ1..EQ6), looked up in v4Lookup, then adjusted
+// by its severity distance to the highest vector of that MacroVector.
+// Spec: https://www.first.org/cvss/v4.0/specification-document (section 8)
+
+var v4Values = map[string][]string{
+	"AV": {"N", "A", "L", "P"},
+	"AC": {"L", "H"},
+	"AT": {"N", "P"},
+	"PR": {"N", "L", "H"},
+	"UI": {"N", "P", "A"},
+	"VC": {"H", "L", "N"},
+	"VI": {"H", "L", "N"},
+	"VA": {"H", "L", "N"},
+	"SC": {"H", "L", "N"},
+	"SI": {"H", "L", "N"},
+	"SA": {"H", "L", "N"},
+}
+
+var v4Optional = map[string][]string{
+	"E":   {"X", "A", "P", "U"},
+	"CR":  {"X", "H", "M", "L"},
+	"IR":  {"X", "H", "M", "L"},
+	"AR":  {"X", "H", "M", "L"},
+	"MAV": {"X", "N", "A", "L", "P"},
+	"MAC": {"X", "L", "H"},
+	"MAT": {"X", "N", "P"},
+	"MPR": {"X", "N", "L", "H"},
+	"MUI": {"X", "N", "P", "A"},
+	"MVC": {"X", "H", "L", "N"},
+	"MVI": {"X", "H", "L", "N"},
+	"MVA": {"X", "H", "L", "N"},
+	"MSC": {"X", "H", "L", "N"},
+	"MSI": {"X", "S", "H", "L", "N"},
+	"MSA": {"X", "S", "H", "L", "N"},
+	"S":   {"X", "N", "P"},
+	"AU":  {"X", "N", "Y"},
+	"R":   {"X", "A", "U", "I"},
+	"V":   {"X", "D", "C"},
+	"RE":  {"X", "L", "M", "H"},
+	"U":   {"X", "Clear", "Green", "Amber", "Red"},
+}
+
+// Severity levels per metric; lower is more severe (step 0.1 in the reference).
+var v4Levels = map[string]map[string]float64{
+	"AV": {"N": 0.0, "A": 0.1, "L": 0.2, "P": 0.3},
+	"PR": {"N": 0.0, "L": 0.1, "H": 0.2},
+	"UI": {"N": 0.0, "P": 0.1, "A": 0.2},
+	"AC": {"L": 0.0, "H": 0.1},
+	"AT": {"N": 0.0, "P": 0.1},
+	"VC": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VI": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VA": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"SC": {"H": 0.1, "L": 0.2, "N": 0.3},
+	"SI": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"SA": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"CR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"IR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"AR": {"H": 0.0, "M": 0.1, "L": 0.2},
+}
+
+// Highest severity vectors per EQ level (max_composed in the reference).
+var (
+	v4MaxEQ1 = [][]string{
+		{"AV:N/PR:N/UI:N"},
+		{"AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"},
+		{"AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"},
+	}
+	v4MaxEQ2 = [][]string{
+		{"AC:L/AT:N"},
+		{"AC:H/AT:N", "AC:L/AT:P"},
+	}
+	v4MaxEQ3EQ6 = [][][]string{
+		{
+			{"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"},
+			{"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"},
+		},
+		{
+			{"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"},
+			{"VC:L/VI:H/VA:L/CR:H/IR:M/AR:H", "VC:L/VI:H/VA:H/CR:H/IR:M/AR:M", "VC:H/VI:L/VA:H/CR:M/IR:H/AR:M", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H", "VC:L/VI:L/VA:H/CR:H/IR:H/AR:M"},
+		},
+		{
+			nil,
+			{"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"},
+		},
+	}
+	v4MaxEQ4 = [][]string{
+		{"SC:H/SI:S/SA:S"},
+		{"SC:H/SI:H/SA:H"},
+		{"SC:L/SI:L/SA:L"},
+	}
+)
+
+// Depth of each EQ level, in 0.1 steps (max_severity in the reference).
+var (
+	v4DepthEQ1    = []float64{1, 4, 5}
+	v4DepthEQ2    = []float64{1, 2}
+	v4DepthEQ3EQ6 = [][]float64{{7, 6}, {8, 8}, {0, 10}}
+	v4DepthEQ4    = []float64{6, 5, 4}
+)
+
+type v4Vector map[string]string
+
+// get returns the effective value of a base metric: the modified metric
+// when set, the base metric otherwise, with the spec's defaults for X.
+func (v v4Vector) get(k string) string {
+	switch k {
+	case "E":
+		if e := v["E"]; e != "" && e != "X" {
+			return e
+		}
+		return "A"
+	case "CR", "IR", "AR":
+		if r := v[k]; r != "" && r != "X" {
+			return r
+		}
+		return "H"
+	}
+	if m := v["M"+k]; m != "" && m != "X" {
+		return m
+	}
+	return v[k]
+}
+
+func parseV4(vector string) (v4Vector, error) {
+	m, err := parseMetrics(vector)
+	if err != nil {
+		return nil, err
+	}
+	for k, val := range m {
+		allowed, ok := v4Values[k]
+		if !ok {
+			allowed, ok = v4Optional[k]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown metric %s", k)
+		}
+		if !contains(allowed, val) {
+			return nil, fmt.Errorf("invalid value %s:%s", k, val)
+		}
+	}
+	for k := range v4Values {
+		if _, ok := m[k]; !ok {
+			return nil, fmt.Errorf("missing metric %s", k)
+		}
+	}
+	return v4Vector(m), nil
+}
+
+func (v v4Vector) macroVector() (eq1, eq2, eq3, eq4, eq5, eq6 int) {
+	av, pr, ui := v.get("AV"), v.get("PR"), v.get("UI")
+	switch {
+	case av == "N" && pr == "N" && ui == "N":
+		eq1 = 0
+	case (av == "N" || pr == "N" || ui == "N") && av != "P":
+		eq1 = 1
+	default:
+		eq1 = 2
+	}
+
+	if !(v.get("AC") == "L" && v.get("AT") == "N") {
+		eq2 = 1
+	}
+
+	vc, vi, va := v.get("VC"), v.get("VI"), v.get("VA")
+	switch {
+	case vc == "H" && vi == "H":
+		eq3 = 0
+	case vc == "H" || vi == "H" || va == "H":
+		eq3 = 1
+	default:
+		eq3 = 2
+	}
+
+	switch {
+	case v.get("SI") == "S" || v.get("SA") == "S":
+		eq4 = 0
+	case v.get("SC") == "H" || v.get("SI") == "H" || v.get("SA") == "H":
+		eq4 = 1
+	default:
+		eq4 = 2
+	}
+
+	switch v.get("E") {
+	case "P":
+		eq5 = 1
+	case "U":
+		eq5 = 2
+	}
+
+	if !((v.get("CR") == "H" && vc == "H") || (v.get("IR") == "H" && vi == "H") || (v.get("AR") == "H" && va == "H")) {
+		eq6 = 1
+	}
+	return
+}
+
+func lookupV4(eqs ...int) float64 {
+	key := ""
+	for _, e := range eqs {
+		key += strconv.Itoa(e)
+	}
+	if s, ok := v4Lookup[key]; ok {
+		return s
+	}
+	return math.NaN()
+}
+
+func scoreV4(vector string) (float64, error) {
+	v, err := parseV4(vector)
+	if err != nil {
+		return 0, err
+	}
+
+	// no impact on the vulnerable or subsequent system
+	none := true
+	for _, k := range []string{"VC", "VI", "VA", "SC", "SI", "SA"} {
+		if v.get(k) != "N" {
+			none = false
+			break
+		}
+	}
+	if none {
+		return 0, nil
+	}
+
+	eq1, eq2, eq3, eq4, eq5, eq6 := v.macroVector()
+	value := lookupV4(eq1, eq2, eq3, eq4, eq5, eq6)
+
+	// score of the next lower MacroVector for each EQ
+	lowerEQ1 := lookupV4(eq1+1, eq2, eq3, eq4, eq5, eq6)
+	lowerEQ2 := lookupV4(eq1, eq2+1, eq3, eq4, eq5, eq6)
+	lowerEQ4 := lookupV4(eq1, eq2, eq3, eq4+1, eq5, eq6)
+	lowerEQ5 := lookupV4(eq1, eq2, eq3, eq4, eq5+1, eq6)
+
+	// EQ3 and EQ6 are joint: 00 can go to 01 or 10, 10 goes to 11, others bump EQ3
+	var lowerEQ3EQ6 float64
+	switch {
+	case eq3 == 0 && eq6 == 0:
+		lowerEQ3EQ6 = math.Max(lookupV4(eq1, eq2, eq3, eq4, eq5, eq6+1), lookupV4(eq1, eq2, eq3+1, eq4, eq5, eq6))
+	case eq3 == 1 && eq6 == 0:
+		lowerEQ3EQ6 = lookupV4(eq1, eq2, eq3, eq4, eq5, eq6+1)
+	default:
+		lowerEQ3EQ6 = lookupV4(eq1, eq2, eq3+1, eq4, eq5, eq6)
+	}
+
+	// find the first highest-severity vector that v does not exceed and
+	// measure how far below it v sits
+	var distEQ1, distEQ2, distEQ3EQ6, distEQ4 float64
+	found := false
+	dist := func(k, maxVal string) float64 {
+		return v4Levels[k][v.get(k)] - v4Levels[k][maxVal]
+	}
+search:
+	for _, m1 := range v4MaxEQ1[eq1] {
+		for _, m2 := range v4MaxEQ2[eq2] {
+			for _, m36 := range v4MaxEQ3EQ6[eq3][eq6] {
+				for _, m4 := range v4MaxEQ4[eq4] {
+					ref, _ := parseMetrics(m1 + "/" + m2 + "/" + m36 + "/" + m4)
+					d := map[string]float64{}
+					ok := true
+					for k, mv := range ref {
+						d[k] = dist(k, mv)
+						if d[k] < 0 {
+							ok = false
+							break
+						}
+					}
+					if !ok {
+						continue
+					}
+					distEQ1 = d["AV"] + d["PR"] + d["UI"]
+					distEQ2 = d["AC"] + d["AT"]
+					distEQ3EQ6 = d["VC"] + d["VI"] + d["VA"] + d["CR"] + d["IR"] + d["AR"]
+					distEQ4 = d["SC"] + d["SI"] + d["SA"]
+					found = true
+					break search
+				}
+			}
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no reference vector for MacroVector %d%d%d%d%d%d", eq1, eq2, eq3, eq4, eq5, eq6)
+	}
+
+	const step = 0.1
+	var sum float64
+	n := 0
+	add := func(lower, distance, depth float64) {
+		if math.IsNaN(lower) {
+			return
+		}
+		n++
+		sum += (value - lower) * (distance / (depth * step))
+	}
+	add(lowerEQ1, distEQ1, v4DepthEQ1[eq1])
+	add(lowerEQ2, distEQ2, v4DepthEQ2[eq2])
+	add(lowerEQ3EQ6, distEQ3EQ6, v4DepthEQ3EQ6[eq3][eq6])
+	add(lowerEQ4, distEQ4, v4DepthEQ4[eq4])
+	// EQ5 has a single metric, so its distance to the max is always 0
+	add(lowerEQ5, 0, 1)
+
+	if n > 0 {
+		value -= sum / float64(n)
+	}
+	return round1(math.Min(math.Max(value, 0), 10)), nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -3,10 +3,11 @@ package drift
 import (
 	"context"
 	"fmt"
+	"os"
 )
 
 func DetectLayerDrift(ctx context.Context, image string) error {
 	// TODO: compare layer digests vs baseline
-	fmt.Println("[drift] Detecting layer drift for:", image)
+	fmt.Fprintln(os.Stderr, "[drift] Detecting layer drift for:", image)
 	return nil
 }
@@ -206,3 +206,33 @@ func GetCompressedLayerDigests(refStr string) ([]string, error) {
 	}
 	return meta.CompressedLayerDigests, nil
 }
+
+// ResolveDigest returns the manifest digest the reference currently points to.
+// For multi-arch images this is the index digest, which is what signatures
+// and attestations are attached to.
+func ResolveDigest(ctx context.Context, refStr string) (string, error) {
+	ref, err := name.ParseReference(refStr)
+	if err != nil {
+		return "", fmt.Errorf("error parsing reference %q: %w ", refStr, err)
+	}
+
+	if d, ok := ref.(name.Digest); ok {
+		return d.DigestStr(), nil
+	}
+
+	remoteOpts := []remote.Option{
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithContext(ctx),
+	}
+
+	// HEAD is cheap but not every registry supports it; fall back to GET
+	desc, err := remote.Head(ref, remoteOpts...)
+	if err != nil {
+		full, getErr := remote.Get(ref, remoteOpts...)
+		if getErr != nil {
+			return "", fmt.Errorf("resolving digest for %q: %w", refStr, getErr)
+		}
+		return full.Digest.String(), nil
+	}
+	return desc.Digest.String(), nil
+}
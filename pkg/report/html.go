@@ -0,0 +1,85 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register("html", RendererFunc(renderHTML))
+}
+
+// The report is a single file with inline styles and no scripts so it can
+// be archived as a CI artifact and opened offline.
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"icon":    func(s Status) string { return statusIcons[s] },
+	"fixedIn": fixedIn,
+	"exploit": exploit,
+	"join":    strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>provavalidator report: {{.Image}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { font-size: 1.4rem; }
+h1 code { font-size: 1.2rem; }
+table { border-collapse: collapse; margin: 1rem 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.verdict { font-weight: bold; padding: 6px 10px; display: inline-block; border-radius: 4px; }
+.verdict.pass { background: #dafbe1; }
+.verdict.fail { background: #ffebe9; }
+.sev-critical { color: #8b0000; font-weight: bold; }
+.sev-high { color: #cf222e; font-weight: bold; }
+.sev-medium { color: #9a6700; }
+.sev-low, .sev-unknown { color: #57606a; }
+.muted { color: #57606a; font-size: 0.9rem; }
+ul.details { margin: 0; padding-left: 1.2rem; }
+</style>
+</head>
+<body>
+<h1>provavalidator report for <code>{{.Image}}</code></h1>
+<p class="muted">Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}</p>
+{{with .Counts}}{{if $.Passed}}<p class="verdict pass">{{icon "pass"}} Passed: {{.Total}} checks</p>
+{{else}}<p class="verdict fail">{{icon "fail"}} Failed: {{.Failed}} failed, {{.Errors}} errors of {{.Total}} checks</p>
+{{end}}{{end}}
+{{if .Checks}}<h2>Checks</h2>
+<table>
+<tr><th>Check</th><th>Status</th><th>Details</th></tr>
+{{range .Checks}}<tr><td>{{.Name}}</td><td>{{icon .Status}} {{.Status}}</td><td>{{.Message}}{{if .Details}}<ul class="details">{{range .Details}}<li>{{.}}</li>{{end}}</ul>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Summary}}<h2>Vulnerabilities</h2>
+<table>
+<tr><th>Critical</th><th>High</th><th>Medium</th><th>Low</th><th>Unknown</th><th>Total</th><th>Known exploited</th></tr>
+<tr><td>{{.Critical}}</td><td>{{.High}}</td><td>{{.Medium}}</td><td>{{.Low}}</td><td>{{.Unknown}}</td><td>{{.Total}}</td><td>{{.KnownExploited}}</td></tr>
+</table>
+{{end}}
+{{with .Delta}}<h2>Changes since <code>{{$.Baseline}}</code></h2>
+<table>
+<tr><th>New</th><th>Fixed</th><th>Unchanged</th></tr>
+<tr><td>{{len .New}}</td><td>{{len .Fixed}}</td><td>{{len .Unchanged}}</td></tr>
+</table>
+{{if .New}}<h3>New vulnerabilities</h3>
+{{template "findings" .New}}{{end}}
+{{if .Fixed}}<h3>Fixed vulnerabilities</h3>
+{{template "findings" .Fixed}}{{end}}
+{{end}}
+{{with .Coverage}}<p>Coverage: {{printf "%.1f" .Percent}}% of {{.Packages}} packages scanned ({{len .Skipped}} skipped)</p>
+{{end}}
+{{if .Findings}}<h2>Findings ({{len .Findings}})</h2>
+{{template "findings" .Findings}}{{end}}
+</body>
+</html>
+{{define "findings"}}<table>
+<tr><th>Severity</th><th>ID</th><th>Package</th><th>Version</th><th>Fixed in</th><th>Exploit</th><th>Summary</th></tr>
+{{range .}}<tr><td class="sev-{{.Severity}}">{{.Severity}}</td><td><a href="https://osv.dev/vulnerability/{{.VulnID}}">{{.VulnID}}</a>{{if .Aliases}}<br><span class="muted">{{join .Aliases ", "}}</span>{{end}}</td><td>{{.PackageName}}</td><td>{{.PackageVersion}}</td><td>{{fixedIn .FixedVersions}}</td><td>{{exploit .}}</td><td>{{.Summary}}</td></tr>
+{{end}}</table>
+{{end}}`))
+
+func renderHTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
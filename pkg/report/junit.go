@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("junit", RendererFunc(renderJUnit))
+}
+
+// JUnit XML as read by Jenkins, GitLab and GitHub test reporters.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitProblem `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+func renderJUnit(w io.Writer, r *Report) error {
+	counts := r.Counts()
+	var total time.Duration
+	suite := junitSuite{
+		Name:      r.Image,
+		Tests:     counts.Total,
+		Failures:  counts.Failed,
+		Errors:    counts.Errors,
+		Skipped:   counts.Skipped,
+		Timestamp: r.Generated.Format(time.RFC3339),
+	}
+
+	for _, c := range r.Checks {
+		total += c.Duration
+		tc := junitCase{
+			Name:      c.Name,
+			ClassName: "provavalidator." + strings.SplitN(c.Name, "/", 2)[0],
+			Time:      seconds(c.Duration),
+		}
+		problem := &junitProblem{Message: c.Message, Type: c.Name, Text: strings.Join(c.Details, "\n")}
+		switch c.Status {
+		case StatusFail:
+			tc.Failure = problem
+		case StatusError:
+			tc.Error = problem
+		case StatusSkipped:
+			tc.Skipped = &junitProblem{Message: c.Message}
+		default:
+			tc.SystemOut = c.Message
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = seconds(total)
+
+	doc := junitSuites{
+		Name:     "provavalidator",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("report: encode junit: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
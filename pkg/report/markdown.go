@@ -0,0 +1,123 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+func init() {
+	Register("markdown", RendererFunc(renderMarkdown))
+}
+
+var statusIcons = map[Status]string{
+	StatusPass:    "✅",
+	StatusFail:    "❌",
+	StatusError:   "⚠️",
+	StatusSkipped: "⏭️",
+}
+
+// renderMarkdown writes a summary suited to PR comments: the check table
+// and severity counts up front, findings folded in a <details> block.
+func renderMarkdown(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## provavalidator report for `%s`\n\n", r.Image)
+
+	c := r.Counts()
+	if r.Passed() {
+		fmt.Fprintf(&b, "**Result:** %s passed (%d checks)\n\n", statusIcons[StatusPass], c.Total)
+	} else {
+		fmt.Fprintf(&b, "**Result:** %s failed (%d failed, %d errors of %d checks)\n\n", statusIcons[StatusFail], c.Failed, c.Errors, c.Total)
+	}
+
+	if len(r.Checks) > 0 {
+		b.WriteString("| Check | Status | Details |\n|---|---|---|\n")
+		for _, ch := range r.Checks {
+			fmt.Fprintf(&b, "| %s | %s %s | %s |\n", mdCell(ch.Name), statusIcons[ch.Status], ch.Status, mdCell(ch.Message))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, ch := range r.Checks {
+		if len(ch.Details) == 0 || ch.Status == StatusPass {
+			continue
+		}
+		fmt.Fprintf(&b, "<details>\n<summary>%s: %d items</summary>\n\n", mdCell(ch.Name), len(ch.Details))
+		for _, d := range ch.Details {
+			fmt.Fprintf(&b, "- %s\n", mdCell(d))
+		}
+		b.WriteString("\n</details>\n\n")
+	}
+
+	if s := r.Summary; s != nil {
+		b.WriteString("### Vulnerabilities\n\n")
+		b.WriteString("| Critical | High | Medium | Low | Unknown | Total | Known exploited |\n|---|---|---|---|---|---|---|\n")
+		fmt.Fprintf(&b, "| %d | %d | %d | %d | %d | %d | %d |\n\n", s.Critical, s.High, s.Medium, s.Low, s.Unknown, s.Total, s.KnownExploited)
+	}
+
+	if d := r.Delta; d != nil {
+		fmt.Fprintf(&b, "### Changes since `%s`\n\n", r.Baseline)
+		b.WriteString("| New | Fixed | Unchanged |\n|---|---|---|\n")
+		fmt.Fprintf(&b, "| %d | %d | %d |\n\n", len(d.New), len(d.Fixed), len(d.Unchanged))
+		writeFindingTable(&b, "New vulnerabilities", d.New)
+		writeFindingTable(&b, "Fixed vulnerabilities", d.Fixed)
+	}
+
+	if cov := r.Coverage; cov != nil {
+		fmt.Fprintf(&b, "Coverage: %.1f%% of %d packages scanned (%d skipped)\n\n", cov.Percent, cov.Packages, len(cov.Skipped))
+	}
+
+	writeFindingTable(&b, "Findings", r.Findings)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFindingTable folds findings into a collapsible table.
+func writeFindingTable(b *strings.Builder, title string, findings []vuln.Finding) {
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "<details>\n<summary>%s (%d)</summary>\n\n", title, len(findings))
+	b.WriteString("| Severity | ID | Package | Version | Fixed in | Exploit | Summary |\n|---|---|---|---|---|---|---|\n")
+	for _, f := range findings {
+		fmt.Fprintf(b, "| %s | [%s](https://osv.dev/vulnerability/%s) | %s | %s | %s | %s | %s |\n",
+			f.Severity, mdCell(f.VulnID), url.PathEscape(f.VulnID), mdCell(f.PackageName), mdCell(f.PackageVersion),
+			mdCell(fixedIn(f.FixedVersions)), exploit(f), mdCell(f.Summary))
+	}
+	b.WriteString("\n</details>\n\n")
+}
+
+// mdCell keeps a value from breaking out of its table cell or injecting
+// markup: advisory text is third-party and may contain HTML.
+func mdCell(s string) string {
+	s = strings.ReplaceAll(html.EscapeString(s), "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// exploit summarises KEV and EPSS data, "-" when there is none.
+func exploit(f vuln.Finding) string {
+	var parts []string
+	if f.KnownExploited != nil {
+		parts = append(parts, "KEV")
+	}
+	if f.EPSS != nil {
+		parts = append(parts, fmt.Sprintf("EPSS %.1f%%", f.EPSS.Probability*100))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func fixedIn(versions []string) string {
+	if len(versions) == 0 {
+		return "-"
+	}
+	return strings.Join(versions, ", ")
+}
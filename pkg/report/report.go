@@ -0,0 +1,152 @@
+// Package report renders check and scan results for humans and CI systems.
+// Renderers are looked up by format name so commands can offer the same
+// --format values.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+type Status string
+
+const (
+	StatusPass    Status = "pass"
+	StatusFail    Status = "fail"
+	StatusError   Status = "error"
+	StatusSkipped Status = "skipped"
+)
+
+// Check is one check or policy rule; JUnit renders each as a testcase.
+type Check struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	Details  []string      `json:"details,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+type Report struct {
+	Image     string         `json:"image"`
+	Generated time.Time      `json:"generated"`
+	Checks    []Check        `json:"checks"`
+	Summary   *vuln.Summary  `json:"summary,omitempty"`
+	Findings  []vuln.Finding `json:"findings,omitempty"`
+	Coverage  *vuln.Coverage `json:"coverage,omitempty"`
+	Baseline  string         `json:"baseline,omitempty"`
+	Delta     *vuln.Delta    `json:"delta,omitempty"`
+}
+
+func New(image string) *Report {
+	return &Report{Image: image, Generated: time.Now().UTC()}
+}
+
+func (r *Report) Add(checks ...Check) {
+	r.Checks = append(r.Checks, checks...)
+}
+
+// SetScan attaches vulnerability scan results.
+func (r *Report) SetScan(findings []vuln.Finding, cov *vuln.Coverage) {
+	s := vuln.Summarize(findings)
+	r.Summary = &s
+	r.Findings = findings
+	r.Coverage = cov
+}
+
+// SetDelta attaches the comparison with a baseline scan.
+func (r *Report) SetDelta(baseline string, d vuln.Delta) {
+	r.Baseline = baseline
+	r.Delta = &d
+}
+
+// Counts is the number of checks per status.
+type Counts struct {
+	Total, Passed, Failed, Errors, Skipped int
+}
+
+func (r *Report) Counts() Counts {
+	c := Counts{Total: len(r.Checks)}
+	for _, ch := range r.Checks {
+		switch ch.Status {
+		case StatusPass:
+			c.Passed++
+		case StatusFail:
+			c.Failed++
+		case StatusError:
+			c.Errors++
+		case StatusSkipped:
+			c.Skipped++
+		}
+	}
+	return c
+}
+
+// Passed is true when no check failed or errored.
+func (r *Report) Passed() bool {
+	c := r.Counts()
+	return c.Failed == 0 && c.Errors == 0
+}
+
+// Err summarises failed checks as an error for the command's exit status.
+func (r *Report) Err() error {
+	var failed []Check
+	for _, c := range r.Checks {
+		if c.Status == StatusFail || c.Status == StatusError {
+			failed = append(failed, c)
+		}
+	}
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		if failed[0].Message != "" {
+			return errors.New(failed[0].Message)
+		}
+		return fmt.Errorf("check %s failed", failed[0].Name)
+	default:
+		names := make([]string, len(failed))
+		for i, c := range failed {
+			names[i] = c.Name
+		}
+		return fmt.Errorf("%d of %d checks failed: %s", len(failed), len(r.Checks), strings.Join(names, ", "))
+	}
+}
+
+type Renderer interface {
+	Render(w io.Writer, r *Report) error
+}
+
+// RendererFunc adapts a function to a Renderer.
+type RendererFunc func(w io.Writer, r *Report) error
+
+func (f RendererFunc) Render(w io.Writer, r *Report) error { return f(w, r) }
+
+var renderers = map[string]Renderer{}
+
+// Register makes a renderer available under a --format name.
+func Register(format string, r Renderer) {
+	renderers[strings.ToLower(format)] = r
+}
+
+func Lookup(format string) (Renderer, error) {
+	if r, ok := renderers[strings.ToLower(format)]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("report: unsupported format %q (use %s)", format, strings.Join(Formats(), ", "))
+}
+
+// Formats lists the registered format names.
+func Formats() []string {
+	out := make([]string, 0, len(renderers))
+	for f := range renderers {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
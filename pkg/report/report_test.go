@@ -0,0 +1,179 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sarif"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+func testReport() *Report {
+	r := New("alpine:3.19")
+	findings := []vuln.Finding{
+		{VulnID: "CVE-2024-0001", PackageName: "openssl", PackageVersion: "3.0.11", Severity: vuln.SeverityHigh,
+			Summary: "a | b <script>", FixAvailable: true, FixedVersions: []string{"3.0.13"}},
+		{VulnID: "GHSA-xxxx", PackageName: "left-pad", PackageVersion: "1.0.0", Severity: vuln.SeverityLow},
+	}
+	cov := vuln.Coverage{Packages: 10, Queryable: 9, Percent: 90}
+	r.SetScan(findings, &cov)
+	r.Add(Check{Name: "attestation", Status: StatusPass, Duration: 1500 * time.Millisecond})
+	v := vuln.Evaluate(findings, &cov, vuln.Policy{FailOn: vuln.SeverityHigh, MinCoverage: 95})
+	r.Add(ScanChecks(findings, &v)...)
+	r.Add(Check{Name: "drift", Status: StatusError, Message: "registry unavailable"})
+	return r
+}
+
+func TestScanChecks(t *testing.T) {
+	r := testReport()
+	if len(r.Checks) != 5 {
+		t.Fatalf("expected 5 checks, got %+v", r.Checks)
+	}
+	if c := r.Checks[2]; c.Name != "policy/fail-on" || c.Status != StatusFail || len(c.Details) != 1 {
+		t.Errorf("unexpected fail-on check: %+v", c)
+	}
+	if c := r.Checks[3]; c.Name != "policy/min-coverage" || c.Status != StatusFail {
+		t.Errorf("unexpected coverage check: %+v", c)
+	}
+	if got := r.Counts(); got != (Counts{Total: 5, Passed: 2, Failed: 2, Errors: 1}) {
+		t.Errorf("unexpected counts: %+v", got)
+	}
+	if r.Passed() {
+		t.Error("expected report to fail")
+	}
+	if err := r.Err(); err == nil || !strings.Contains(err.Error(), "3 of 5 checks failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	v := vuln.Evaluate(nil, nil, vuln.Policy{FailOn: vuln.SeverityCritical})
+	ok := ScanChecks(nil, &v)
+	if len(ok) != 2 || ok[1].Status != StatusPass {
+		t.Errorf("expected a passing fail-on check, got %+v", ok)
+	}
+}
+
+func TestRenderJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderJUnit(&buf, testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid junit xml: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 5 || doc.Failures != 2 || doc.Errors != 1 || len(doc.Suites) != 1 {
+		t.Fatalf("unexpected totals: %+v", doc)
+	}
+	cases := doc.Suites[0].Cases
+	if cases[0].Time != "1.500" || cases[0].Failure != nil {
+		t.Errorf("unexpected passing case: %+v", cases[0])
+	}
+	if f := cases[2].Failure; f == nil || !strings.Contains(f.Text, "CVE-2024-0001 in openssl@3.0.11") {
+		t.Errorf("expected failure with details: %+v", cases[2])
+	}
+	if cases[2].ClassName != "provavalidator.policy" || cases[4].Error == nil {
+		t.Errorf("unexpected cases: %+v", cases)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderMarkdown(&buf, testReport()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"**Result:** ❌ failed",
+		"| policy/fail-on | ❌ fail |",
+		"| 0 | 1 | 0 | 1 | 0 | 2 | 0 |",
+		"<summary>Findings (2)</summary>",
+		`a \| b &lt;script&gt;`,
+		"| 3.0.13 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script>") {
+		t.Errorf("markdown passed raw HTML through:\n%s", out)
+	}
+}
+
+func TestRenderMarkdown_EscapesDetails(t *testing.T) {
+	r := New("alpine:3.19")
+	r.Add(Check{Name: "sbom-crosscheck", Status: StatusFail, Details: []string{"not attested: x</details><img src=x> 1.0"}})
+	var buf bytes.Buffer
+	if err := renderMarkdown(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if n := strings.Count(out, "</details>"); n != 1 {
+		t.Errorf("expected the one </details> the renderer writes, got %d:\n%s", n, out)
+	}
+	if !strings.Contains(out, "- not attested: x&lt;/details&gt;&lt;img src=x&gt; 1.0") {
+		t.Errorf("detail not escaped:\n%s", out)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderHTML(&buf, testReport()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "<script>") || !strings.Contains(out, "&lt;script&gt;") {
+		t.Error("summary was not escaped")
+	}
+	for _, want := range []string{"<title>provavalidator report: alpine:3.19</title>", "Failed: 2 failed, 1 errors of 5 checks", `class="sev-high"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, f := range []string{"text", "json", "sarif", "junit", "markdown", "HTML"} {
+		if _, err := Lookup(f); err != nil {
+			t.Errorf("lookup %s: %v", f, err)
+		}
+	}
+	if _, err := Lookup("pdf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestRenderSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderSARIF(&buf, testReport()); err != nil {
+		t.Fatal(err)
+	}
+	var log sarif.Log
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, buf.String())
+	}
+
+	results := map[string]sarif.Level{}
+	for _, res := range log.Runs[0].Results {
+		results[res.RuleID] = res.Level
+	}
+	want := map[string]sarif.Level{
+		"CVE-2024-0001":                         sarif.LevelError,
+		"GHSA-xxxx":                             sarif.LevelNote,
+		sarif.PolicyRulePrefix + "fail-on":      sarif.LevelError,
+		sarif.PolicyRulePrefix + "min-coverage": sarif.LevelError,
+		sarif.CheckRulePrefix + "drift":         sarif.LevelWarning,
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("unexpected results: %v", results)
+	}
+	inv := log.Runs[0].Invocations
+	if len(inv) != 1 || inv[0].ExecutionSuccessful || inv[0].Properties["checksPassed"] != false {
+		t.Errorf("unexpected invocation: %+v", inv)
+	}
+}
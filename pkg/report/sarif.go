@@ -0,0 +1,55 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sarif"
+)
+
+func init() {
+	Register("sarif", RendererFunc(renderSARIF))
+}
+
+// renderSARIF writes the findings as vuln --format sarif does, plus one
+// result per check that failed (level error) or could not run (warning).
+// Policy checks keep the rule IDs vuln uses for them.
+func renderSARIF(w io.Writer, r *Report) error {
+	log := sarif.Build(r.Findings, sarif.Options{ImageRef: r.Image, Delta: r.Delta})
+
+	for _, c := range r.Checks {
+		level := sarif.LevelError
+		switch c.Status {
+		case StatusFail:
+		case StatusError:
+			level = sarif.LevelWarning
+		default:
+			continue
+		}
+
+		id := sarif.CheckRulePrefix + c.Name
+		if rule, ok := strings.CutPrefix(c.Name, "policy/"); ok {
+			id = sarif.PolicyRulePrefix + rule
+		}
+		msg := c.Message
+		if msg == "" {
+			msg = "check " + c.Name + " " + string(c.Status)
+		}
+		props := map[string]any{"status": c.Status}
+		if len(c.Details) > 0 {
+			props["details"] = c.Details
+		}
+		log.AddCheck(id, msg, level, r.Image, props)
+	}
+
+	counts := r.Counts()
+	log.Runs[0].Invocations = []sarif.Invocation{{
+		ExecutionSuccessful: counts.Errors == 0,
+		Properties:          map[string]any{"checksPassed": r.Passed()},
+	}}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,34 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+// ScanChecks turns a vulnerability scan into checks: the scan itself plus
+// one per policy rule in v. v may be nil when no policy applies.
+func ScanChecks(findings []vuln.Finding, v *vuln.Verdict) []Check {
+	s := vuln.Summarize(findings)
+	checks := []Check{{
+		Name:   "vulnerabilities",
+		Status: StatusPass,
+		Message: fmt.Sprintf("%d vulnerabilities (%d critical, %d high, %d medium, %d low, %d unknown)",
+			s.Total, s.Critical, s.High, s.Medium, s.Low, s.Unknown),
+	}}
+	if v == nil {
+		return checks
+	}
+
+	for _, r := range v.Rules {
+		c := Check{Name: "policy/" + r.Rule, Status: StatusPass, Message: r.Message}
+		if !r.Passed {
+			c.Status = StatusFail
+		}
+		for _, f := range r.Findings {
+			c.Details = append(c.Details, fmt.Sprintf("[%s] %s in %s@%s", f.Severity, f.VulnID, f.PackageName, f.PackageVersion))
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
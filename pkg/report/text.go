@@ -0,0 +1,51 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+func init() {
+	Register("text", RendererFunc(renderText))
+	Register("json", RendererFunc(renderJSON))
+}
+
+func renderText(w io.Writer, r *Report) error {
+	fmt.Fprintf(w, "Checks for %s:\n", r.Image)
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "  [%s] %s", strings.ToUpper(string(c.Status)), c.Name)
+		if c.Message != "" {
+			fmt.Fprintf(w, ": %s", c.Message)
+		}
+		fmt.Fprintln(w)
+		for _, d := range c.Details {
+			fmt.Fprintf(w, "      - %s\n", d)
+		}
+	}
+
+	if r.Delta != nil {
+		fmt.Fprintln(w)
+		vuln.WriteDelta(w, r.Baseline, *r.Delta)
+	}
+	if r.Summary != nil {
+		vuln.WriteSummary(w, *r.Summary)
+	}
+	vuln.WriteCoverage(w, r.Coverage)
+
+	c := r.Counts()
+	fmt.Fprintf(w, "\n%d checks: %d passed, %d failed, %d errors, %d skipped\n", c.Total, c.Passed, c.Failed, c.Errors, c.Skipped)
+	return nil
+}
+
+func renderJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		*Report
+		Passed bool `json:"passed"`
+	}{r, r.Passed()})
+}
@@ -0,0 +1,323 @@
+// Package sarif renders vulnerability findings and policy violations as
+// SARIF 2.1.0 so they show up in code scanning dashboards.
+package sarif
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+const (
+	toolName = "provavalidator"
+	toolURI  = "https://github.com/kiptoonkipkurui/provavalidator"
+
+	// PolicyRulePrefix prefixes the rule IDs of failed policy rules, e.g.
+	// provavalidator/policy/min-coverage.
+	PolicyRulePrefix = "provavalidator/policy/"
+
+	// CheckRulePrefix prefixes the rule IDs of failed checks, e.g.
+	// provavalidator/check/attestation.
+	CheckRulePrefix = "provavalidator/check/"
+)
+
+type Options struct {
+	// ImageRef is used as the artifact location for findings without a
+	// path inside the image.
+	ImageRef string
+
+	// Verdict, when set, raises findings that violate the policy to level
+	// error and adds a result for every failed rule.
+	Verdict *vuln.Verdict
+
+	// Delta, when set, records each result's baselineState (new or unchanged).
+	Delta *vuln.Delta
+}
+
+// Build makes a single-run log with one rule per vulnerability ID and one
+// result per finding.
+func Build(findings []vuln.Finding, opts Options) *Log {
+	run := Run{
+		Tool:    Tool{Driver: Driver{Name: toolName, InformationURI: toolURI}},
+		Results: make([]Result, 0, len(findings)),
+	}
+	index := map[string]int{}
+
+	for _, f := range findings {
+		i, ok := index[f.VulnID]
+		if !ok {
+			i = len(run.Tool.Driver.Rules)
+			index[f.VulnID] = i
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, ruleFor(f))
+		} else {
+			raiseRule(&run.Tool.Driver.Rules[i], f)
+		}
+		run.Results = append(run.Results, resultFor(f, i, opts))
+	}
+
+	if v := opts.Verdict; v != nil {
+		for _, r := range v.Violations() {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, Rule{
+				ID:                   PolicyRulePrefix + r.Rule,
+				Name:                 "Policy " + r.Rule,
+				ShortDescription:     &Message{Text: "Policy rule " + r.Rule + " failed"},
+				DefaultConfiguration: &Configuration{Level: LevelError},
+				Properties:           &RuleProperties{Tags: []string{"policy"}},
+			})
+			run.Results = append(run.Results, Result{
+				RuleID:     PolicyRulePrefix + r.Rule,
+				RuleIndex:  len(run.Tool.Driver.Rules) - 1,
+				Level:      LevelError,
+				Message:    Message{Text: r.Message},
+				Locations:  []Location{imageLocation(opts.ImageRef)},
+				Properties: map[string]any{"policyViolation": r.Rule},
+			})
+		}
+		run.Invocations = []Invocation{{
+			ExecutionSuccessful: true,
+			Properties:          map[string]any{"policyPassed": v.Passed},
+		}}
+	}
+
+	return &Log{Schema: Schema, Version: Version, Runs: []Run{run}}
+}
+
+// AddCheck records a check that did not pass as a rule and a result
+// located at the image, for logs that report more than findings.
+func (l *Log) AddCheck(ruleID, message string, level Level, imageRef string, properties map[string]any) {
+	run := &l.Runs[0]
+	run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, Rule{
+		ID:                   ruleID,
+		Name:                 strings.TrimPrefix(strings.TrimPrefix(ruleID, CheckRulePrefix), PolicyRulePrefix),
+		ShortDescription:     &Message{Text: ruleID + " did not pass"},
+		DefaultConfiguration: &Configuration{Level: level},
+		Properties:           &RuleProperties{Tags: []string{"check"}},
+	})
+	run.Results = append(run.Results, Result{
+		RuleID:     ruleID,
+		RuleIndex:  len(run.Tool.Driver.Rules) - 1,
+		Level:      level,
+		Message:    Message{Text: message},
+		Locations:  []Location{imageLocation(imageRef)},
+		Properties: properties,
+	})
+}
+
+func ruleFor(f vuln.Finding) Rule {
+	short := f.Summary
+	if short == "" {
+		short = fmt.Sprintf("%s in %s", f.VulnID, f.PackageName)
+	}
+	full := f.Details
+	if full == "" {
+		full = short
+	}
+
+	return Rule{
+		ID:                   f.VulnID,
+		Name:                 f.VulnID,
+		ShortDescription:     &Message{Text: short},
+		FullDescription:      &Message{Text: full},
+		Help:                 helpFor(f),
+		HelpURI:              "https://osv.dev/vulnerability/" + f.VulnID,
+		DefaultConfiguration: &Configuration{Level: levelFor(f.Severity)},
+		Properties: &RuleProperties{
+			SecuritySeverity: securitySeverity(f),
+			Tags:             []string{"security", "vulnerability", string(f.Severity)},
+		},
+	}
+}
+
+// raiseRule keeps a rule at the worst severity seen: vendor severities can
+// differ per package for the same ID.
+func raiseRule(r *Rule, f vuln.Finding) {
+	if levelRank(levelFor(f.Severity)) > levelRank(r.DefaultConfiguration.Level) {
+		r.DefaultConfiguration.Level = levelFor(f.Severity)
+		r.Properties.Tags[2] = string(f.Severity)
+	}
+	if s := securitySeverity(f); score(s) > score(r.Properties.SecuritySeverity) {
+		r.Properties.SecuritySeverity = s
+	}
+}
+
+func helpFor(f vuln.Finding) *Message {
+	var text, md strings.Builder
+
+	fmt.Fprintf(&text, "%s: %s\n", f.VulnID, f.Summary)
+	fmt.Fprintf(&md, "**%s** %s\n\n", f.VulnID, f.Summary)
+	md.WriteString("| Severity | CVSS | Aliases | Fixed in |\n|---|---|---|---|\n")
+	fmt.Fprintf(&md, "| %s | %s | %s | %s |\n\n", f.Severity, cvssText(f), strings.Join(f.Aliases, ", "), fixText(f))
+
+	fmt.Fprintf(&text, "Severity: %s (CVSS %s)\n", f.Severity, cvssText(f))
+	if len(f.Aliases) > 0 {
+		fmt.Fprintf(&text, "Aliases: %s\n", strings.Join(f.Aliases, ", "))
+	}
+	fmt.Fprintf(&text, "Fixed in: %s\n", fixText(f))
+	if f.Details != "" {
+		text.WriteString("\n" + f.Details + "\n")
+		md.WriteString(f.Details + "\n")
+	}
+	return &Message{Text: text.String(), Markdown: md.String()}
+}
+
+func resultFor(f vuln.Finding, rule int, opts Options) Result {
+	r := Result{
+		RuleID:    f.VulnID,
+		RuleIndex: rule,
+		Level:     levelFor(f.Severity),
+		Message:   Message{Text: messageFor(f)},
+		Properties: map[string]any{
+			"packageName":    f.PackageName,
+			"packageVersion": f.PackageVersion,
+			"severity":       f.Severity,
+		},
+	}
+	if f.PURL != "" {
+		r.Properties["purl"] = f.PURL
+	}
+	if len(f.FixedVersions) > 0 {
+		r.Properties["fixedVersions"] = f.FixedVersions
+	}
+	if len(f.IntroducedVia) > 0 {
+		r.Properties["introducedVia"] = f.IntroducedVia
+	}
+	if f.KnownExploited != nil {
+		r.Properties["knownExploited"] = true
+	}
+	if f.EPSS != nil {
+		r.Properties["epss"] = f.EPSS.Probability
+		r.Properties["epssPercentile"] = f.EPSS.Percentile
+	}
+	if d := opts.Delta; d != nil {
+		r.BaselineState = "new"
+		for _, u := range d.Unchanged {
+			if u.VulnID == f.VulnID && u.PackageName == f.PackageName && u.PackageVersion == f.PackageVersion {
+				r.BaselineState = "unchanged"
+				break
+			}
+		}
+	}
+
+	if v := opts.Verdict; v != nil {
+		var rules []string
+		for _, rule := range v.Violations() {
+			for _, ref := range rule.Findings {
+				if ref.Matches(f) {
+					rules = append(rules, rule.Rule)
+					break
+				}
+			}
+		}
+		if len(rules) > 0 {
+			r.Level = LevelError
+			r.Properties["policyViolation"] = strings.Join(rules, ",")
+		}
+	}
+
+	logical := []LogicalLocation{{
+		Name:               f.PackageName + "@" + f.PackageVersion,
+		FullyQualifiedName: f.PURL,
+		Kind:               "package",
+	}}
+	for _, loc := range f.Locations {
+		r.Locations = append(r.Locations, Location{
+			PhysicalLocation: &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: strings.TrimPrefix(loc, "/")}},
+			LogicalLocations: logical,
+		})
+	}
+	if len(r.Locations) == 0 {
+		l := imageLocation(opts.ImageRef)
+		l.LogicalLocations = logical
+		r.Locations = []Location{l}
+	}
+	return r
+}
+
+func messageFor(f vuln.Finding) string {
+	msg := fmt.Sprintf("%s (%s) in %s@%s", f.VulnID, f.Severity, f.PackageName, f.PackageVersion)
+	if f.KnownExploited != nil {
+		msg += " [known exploited]"
+	}
+	if f.Summary != "" {
+		msg += ": " + f.Summary
+	}
+	if f.FixAvailable {
+		msg += ". Fixed in " + strings.Join(f.FixedVersions, ", ")
+	}
+	return msg
+}
+
+func imageLocation(image string) Location {
+	if image == "" {
+		image = "image"
+	}
+	return Location{PhysicalLocation: &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: image}}}
+}
+
+func levelFor(s vuln.Severity) Level {
+	switch s {
+	case vuln.SeverityCritical, vuln.SeverityHigh:
+		return LevelError
+	case vuln.SeverityMedium:
+		return LevelWarning
+	default:
+		return LevelNote
+	}
+}
+
+func levelRank(l Level) int {
+	switch l {
+	case LevelError:
+		return 3
+	case LevelWarning:
+		return 2
+	case LevelNote:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// securitySeverity is the CVSS score, or a representative score for the
+// severity bucket when no CVSS data exists (what dashboards sort by).
+func securitySeverity(f vuln.Finding) string {
+	if f.CVSSScore > 0 {
+		return fmt.Sprintf("%.1f", f.CVSSScore)
+	}
+	switch f.Severity {
+	case vuln.SeverityCritical:
+		return "9.5"
+	case vuln.SeverityHigh:
+		return "8.0"
+	case vuln.SeverityMedium:
+		return "5.5"
+	case vuln.SeverityLow:
+		return "2.0"
+	default:
+		return ""
+	}
+}
+
+func score(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func cvssText(f vuln.Finding) string {
+	if f.CVSSScore == 0 {
+		return "n/a"
+	}
+	if f.CVSSVector != "" {
+		return fmt.Sprintf("%.1f %s", f.CVSSScore, f.CVSSVector)
+	}
+	return fmt.Sprintf("%.1f", f.CVSSScore)
+}
+
+func fixText(f vuln.Finding) string {
+	if !f.FixAvailable {
+		return "no fix available"
+	}
+	return strings.Join(f.FixedVersions, ", ")
+}
@@ -0,0 +1,80 @@
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+func TestBuild(t *testing.T) {
+	findings := []vuln.Finding{
+		{
+			VulnID: "CVE-2024-0001", Summary: "overflow", Details: "A long description.",
+			PackageName: "openssl", PackageVersion: "3.0.11", PURL: "pkg:deb/debian/openssl@3.0.11",
+			Severity: vuln.SeverityMedium, CVSSScore: 5.3,
+			FixAvailable: true, FixedVersions: []string{"3.0.13"},
+			Locations: []string{"/var/lib/dpkg/status"},
+		},
+		{
+			VulnID: "CVE-2024-0001", PackageName: "libssl3", PackageVersion: "3.0.11",
+			Severity: vuln.SeverityHigh, CVSSScore: 7.5,
+		},
+		{VulnID: "GHSA-xxxx", PackageName: "left-pad", PackageVersion: "1.0.0", Severity: vuln.SeverityLow},
+	}
+	cov := vuln.Coverage{Packages: 10, Queryable: 5, Percent: 50}
+
+	verdict := vuln.Evaluate(findings, &cov, vuln.Policy{FailOn: vuln.SeverityHigh, MinCoverage: 90})
+	log := Build(findings, Options{ImageRef: "alpine:3.19", Verdict: &verdict})
+
+	if _, err := json.Marshal(log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != Version || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	run := log.Runs[0]
+
+	rules := run.Tool.Driver.Rules
+	if len(rules) != 4 || rules[0].ID != "CVE-2024-0001" || rules[1].ID != "GHSA-xxxx" ||
+		rules[2].ID != PolicyRulePrefix+vuln.RuleFailOn || rules[3].ID != PolicyRulePrefix+vuln.RuleMinCoverage {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+	if rules[0].DefaultConfiguration.Level != LevelError || rules[0].Properties.SecuritySeverity != "7.5" {
+		t.Errorf("rule should take the worst severity: %+v %+v", rules[0].DefaultConfiguration, rules[0].Properties)
+	}
+	if rules[1].Properties.SecuritySeverity != "2.0" || rules[1].ShortDescription.Text != "GHSA-xxxx in left-pad" {
+		t.Errorf("unexpected fallback rule: %+v", rules[1])
+	}
+
+	if len(run.Results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(run.Results))
+	}
+	first := run.Results[0]
+	if first.Level != LevelWarning || first.RuleIndex != 0 || first.Properties["policyViolation"] != nil {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if uri := first.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "var/lib/dpkg/status" {
+		t.Errorf("unexpected uri %q", uri)
+	}
+	if l := first.Locations[0].LogicalLocations[0]; l.FullyQualifiedName != "pkg:deb/debian/openssl@3.0.11" || l.Kind != "package" {
+		t.Errorf("unexpected logical location %+v", l)
+	}
+
+	second := run.Results[1]
+	if second.Level != LevelError || second.Properties["policyViolation"] != "fail-on" {
+		t.Errorf("high finding should violate fail-on: %+v", second)
+	}
+	if uri := second.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "alpine:3.19" {
+		t.Errorf("expected image fallback location, got %q", uri)
+	}
+	if run.Results[2].Level != LevelNote {
+		t.Errorf("low finding should be a note: %+v", run.Results[2])
+	}
+	if r := run.Results[4]; r.RuleID != PolicyRulePrefix+vuln.RuleMinCoverage || r.Level != LevelError || r.RuleIndex != 3 {
+		t.Errorf("unexpected coverage result: %+v", r)
+	}
+	if inv := run.Invocations; len(inv) != 1 || inv[0].Properties["policyPassed"] != false {
+		t.Errorf("expected a failed verdict on the invocation: %+v", inv)
+	}
+}
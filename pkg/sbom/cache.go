@@ -0,0 +1,188 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/anchore/syft/syft"
+)
+
+// cacheSchema is bumped whenever ResolvedSBOM or normalization changes in a
+// way that makes old entries wrong.
+const cacheSchema = "4"
+
+// Cache stores generated SBOMs by image manifest digest. The key also
+// covers the Syft version and cataloger configuration, so upgrading the
+// tool or changing what is cataloged never serves an old result.
+type Cache struct {
+	Dir string
+}
+
+type cacheEntry struct {
+	Key      string        `json:"key"`
+	Tool     string        `json:"tool"`
+	Digest   string        `json:"digest"`
+	StoredAt time.Time     `json:"storedAt"`
+	SBOM     *ResolvedSBOM `json:"sbom"`
+}
+
+// DefaultCacheDir is where generated SBOMs are kept unless told otherwise.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("sbom cache: locate cache dir: %w", err)
+	}
+	return filepath.Join(dir, "provavalidator", "sbom-cache"), nil
+}
+
+// OpenCache prepares a cache in dir (DefaultCacheDir when empty).
+func OpenCache(dir string) (*Cache, error) {
+	if dir == "" {
+		d, err := DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = d
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("sbom cache: create dir: %w", err)
+	}
+	return &Cache{Dir: dir}, nil
+}
+
+// syftVersion reports the Syft module version compiled into this binary.
+func syftVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/anchore/syft" {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// toolKey identifies everything besides the image that shapes the output.
+// Syft picks the host platform from multi-arch indexes, so that counts too.
+func toolKey(cfg *syft.CreateSBOMConfig) (string, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("sbom cache: hash config: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("schema=%s syft=%s platform=%s/%s config=%s",
+		cacheSchema, syftVersion(), runtime.GOOS, runtime.GOARCH, hex.EncodeToString(sum[:8])), nil
+}
+
+func (c *Cache) path(digest, tool string) (string, string) {
+	sum := sha256.Sum256([]byte(digest + "\n" + tool))
+	key := hex.EncodeToString(sum[:])
+	return key, filepath.Join(c.Dir, key[:2], key+".json")
+}
+
+// Get returns the SBOM stored for digest under the given tool key.
+func (c *Cache) Get(digest, tool string) (*ResolvedSBOM, bool) {
+	key, path := c.path(digest, tool)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil || e.Key != key || e.SBOM == nil {
+		return nil, false
+	}
+	return e.SBOM, true
+}
+
+// Put stores s for digest under the given tool key.
+func (c *Cache) Put(digest, tool string, s *ResolvedSBOM) error {
+	key, path := c.path(digest, tool)
+	b, err := json.Marshal(cacheEntry{Key: key, Tool: tool, Digest: digest, StoredAt: time.Now().UTC(), SBOM: s})
+	if err != nil {
+		return fmt.Errorf("sbom cache: marshal: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("sbom cache: create dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("sbom cache: write: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sbom cache: write: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("sbom cache: write: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("sbom cache: write: %w", err)
+	}
+	return nil
+}
+
+// Prune removes entries made by a different tool key than current (they
+// can never be hit again), or every entry when all is set.
+func (c *Cache) Prune(current string, all bool) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(c.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		if !all {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var e cacheEntry
+			if json.Unmarshal(b, &e) == nil && e.Tool == current {
+				return nil
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("sbom cache: prune: %w", err)
+	}
+	return removed, nil
+}
+
+// PruneCache drops stale entries from the default cache (all with all set).
+func PruneCache(dir string, all bool) (int, string, error) {
+	c, err := OpenCache(dir)
+	if err != nil {
+		return 0, "", err
+	}
+	tool, err := toolKey(sbomConfig())
+	if err != nil {
+		return 0, "", err
+	}
+	n, err := c.Prune(tool, all)
+	return n, c.Dir, err
+}
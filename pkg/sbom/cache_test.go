@@ -0,0 +1,75 @@
+package sbom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCache_RoundTripAndInvalidation(t *testing.T) {
+	c, err := OpenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool, err := toolKey(sbomConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	in := &ResolvedSBOM{
+		Source:     SourceGenerated,
+		Format:     "syft-json",
+		Packages:   []NormalizedPackage{{Name: "openssl", Version: "3.0.11-1", Type: "deb", PURL: "pkg:deb/debian/openssl@3.0.11-1"}},
+		Distro:     &Distro{ID: "debian", VersionID: "12"},
+		RawPayload: []byte(`{"artifacts":[]}`),
+	}
+
+	if _, ok := c.Get(digest, tool); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+	if err := c.Put(digest, tool, in); err != nil {
+		t.Fatal(err)
+	}
+	out, ok := c.Get(digest, tool)
+	if !ok || !reflect.DeepEqual(in, out) {
+		t.Fatalf("round trip mismatch:\n in: %+v\nout: %+v", in, out)
+	}
+
+	// a different cataloger config must not hit
+	cfg := sbomConfig()
+	cfg.Search.Scope = "all-layers"
+	other, err := toolKey(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == tool {
+		t.Fatal("config change did not change the tool key")
+	}
+	if _, ok := c.Get(digest, other); ok {
+		t.Fatal("expected miss for a different config")
+	}
+
+	if err := c.Put(digest, other, in); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := c.Prune(tool, false); err != nil || n != 1 {
+		t.Fatalf("expected the other config's entry pruned, got %d (%v)", n, err)
+	}
+	if _, ok := c.Get(digest, tool); !ok {
+		t.Fatal("current entry should survive prune")
+	}
+	if n, _ := c.Prune(tool, true); n != 1 {
+		t.Fatalf("expected prune all to remove 1 entry, got %d", n)
+	}
+}
+
+func TestToolKey_IncludesSyftVersion(t *testing.T) {
+	tool, err := toolKey(sbomConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(tool, "syft="+syftVersion()) || syftVersion() == "" {
+		t.Fatalf("unexpected tool key %q", tool)
+	}
+}
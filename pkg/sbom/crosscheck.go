@@ -0,0 +1,86 @@
+package sbom
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/attestation"
+)
+
+// DefaultCrossCheckTolerance allows for catalogers disagreeing on a few
+// packages (binaries, nested archives) without flagging the SBOM.
+const DefaultCrossCheckTolerance = 0.05
+
+// ExtractAttestedSBOM returns the SBOM published in a verified attestation
+// for the image. It returns attestation.ErrNoSBOMAttestation (wrapped) when
+// there is none.
+func ExtractAttestedSBOM(ctx context.Context, v attestation.Verifier, imageRef string) (*ResolvedSBOM, error) {
+	if v == nil {
+		v = &attestation.CosignVerifier{}
+	}
+	data, _, err := v.ExtractSBOM(ctx, imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("sbom attestation: %w", err)
+	}
+	s, err := resolveBytes(SourceAttestation, data)
+	if err != nil {
+		return nil, fmt.Errorf("sbom attestation: %w", err)
+	}
+	return s, nil
+}
+
+// CrossCheckResult compares an attested SBOM with one generated from the
+// image itself.
+type CrossCheckResult struct {
+	// MissingFromAttested are packages in the image the attested SBOM does
+	// not list; Stale are listed at a different version than installed.
+	MissingFromAttested []NormalizedPackage `json:"missingFromAttested"`
+	Stale               []VersionChange     `json:"stale"`
+
+	// MissingFromImage are attested packages the image does not contain.
+	MissingFromImage []NormalizedPackage `json:"missingFromImage"`
+
+	Attested  int     `json:"attested"`
+	Generated int     `json:"generated"`
+	Tolerance float64 `json:"tolerance"`
+	Passed    bool    `json:"passed"`
+}
+
+// CrossCheck reports how far the attested package list is from the
+// generated one. Each direction fails on its own when more than tolerance
+// (a fraction, 0.05 = 5%) of the packages on that side are unaccounted for:
+// missing or stale packages against the generated count, and packages
+// missing from the image against the attested count.
+func CrossCheck(attested, generated []NormalizedPackage, tolerance float64) CrossCheckResult {
+	d := DiffPackages(attested, generated)
+
+	r := CrossCheckResult{
+		MissingFromAttested: d.Added,
+		Stale:               append(d.Upgraded, d.Downgraded...),
+		MissingFromImage:    d.Removed,
+		Attested:            len(attested),
+		Generated:           len(generated),
+		Tolerance:           tolerance,
+	}
+	sortChanges(r.Stale)
+
+	r.Passed = within(len(r.MissingFromAttested)+len(r.Stale), r.Generated, tolerance) &&
+		within(len(r.MissingFromImage), r.Attested, tolerance)
+	return r
+}
+
+// Summary is a one-line description for check output.
+func (r CrossCheckResult) Summary() string {
+	return fmt.Sprintf("%d of %d image packages missing from the attested SBOM, %d stale, %d attested packages not in the image (tolerance %.0f%%)",
+		len(r.MissingFromAttested), r.Generated, len(r.Stale), len(r.MissingFromImage), r.Tolerance*100)
+}
+
+func within(n, total int, tolerance float64) bool {
+	if n == 0 {
+		return true
+	}
+	if total == 0 {
+		return false
+	}
+	return float64(n)/float64(total) <= tolerance
+}
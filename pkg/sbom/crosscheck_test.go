@@ -0,0 +1,68 @@
+package sbom
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/attestation"
+)
+
+type fakeVerifier struct {
+	data []byte
+	err  error
+}
+
+func (f fakeVerifier) ExtractSBOM(context.Context, string) ([]byte, string, error) {
+	return f.data, "cyclonedx", f.err
+}
+
+func TestExtractAttestedSBOM(t *testing.T) {
+	data, err := os.ReadFile("testdata/cyclonedx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ExtractAttestedSBOM(context.Background(), fakeVerifier{data: data}, "example.com/app:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Source != SourceAttestation || len(s.Packages) != 1 {
+		t.Fatalf("unexpected SBOM: %+v", s)
+	}
+
+	_, err = ExtractAttestedSBOM(context.Background(), fakeVerifier{err: attestation.ErrNoSBOMAttestation}, "example.com/app:1.0")
+	if !errors.Is(err, attestation.ErrNoSBOMAttestation) {
+		t.Fatalf("expected ErrNoSBOMAttestation, got %v", err)
+	}
+}
+
+func TestCrossCheck(t *testing.T) {
+	generated := make([]NormalizedPackage, 0, 20)
+	for _, n := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r", "s", "t"} {
+		generated = append(generated, NormalizedPackage{Name: n, Version: "1.0", Type: "npm"})
+	}
+
+	// one package missing out of 20 is within 5%
+	attested := append([]NormalizedPackage{}, generated[1:]...)
+	r := CrossCheck(attested, generated, DefaultCrossCheckTolerance)
+	if !r.Passed || len(r.MissingFromAttested) != 1 || r.MissingFromAttested[0].Name != "a" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	// a second, stale package is not
+	attested[0].Version = "0.9"
+	r = CrossCheck(attested, generated, DefaultCrossCheckTolerance)
+	if r.Passed || len(r.Stale) != 1 || r.Stale[0].Before != "0.9" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	// attested packages the image lacks are checked on their own
+	attested = append(append([]NormalizedPackage{}, generated...), NormalizedPackage{Name: "ghost", Version: "1.0", Type: "npm"})
+	if r = CrossCheck(attested, generated, 0); r.Passed || len(r.MissingFromImage) != 1 {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+	if r = CrossCheck(attested, generated, 0.1); !r.Passed {
+		t.Fatalf("expected pass within tolerance: %+v", r)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/format/syftjson"
 	syftsobm "github.com/anchore/syft/syft/sbom"
 )
 
@@ -30,6 +31,17 @@ func Decode(r io.Reader) (*DecodeResult, error) {
 
 	decoders := format.NewDecoderCollection(format.Decoders()...)
 
+	if !syftJSONDecodable {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("sbom decode: read input: %w", err)
+		}
+		if id, _ := decoders.Identify(bytes.NewReader(data)); id == syftjson.ID {
+			return nil, fmt.Errorf("sbom decode: syft-json input is not supported by builds with GOEXPERIMENT=jsonv2")
+		}
+		r = bytes.NewReader(data)
+	}
+
 	doc, formatID, info, err := decoders.Decode(r)
 	if err != nil {
 		return nil, fmt.Errorf("sbom decode: unable to decode input: %w", err)
@@ -25,7 +25,6 @@ func TestDecode_CycloneDX(t *testing.T) {
 	}
 }
 
-// TODO: add spdx.json
 func TestDecode_SPDX(t *testing.T) {
 	b, err := os.ReadFile("testdata/spdx.json")
 	if err != nil {
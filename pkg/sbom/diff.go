@@ -0,0 +1,222 @@
+package sbom
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/version"
+)
+
+// VersionChange is a package present on both sides at different versions.
+type VersionChange struct {
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	PURL   string `json:"purl,omitempty"` // as in the newer SBOM
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// LicenseChange is a package whose declared licenses differ between the two
+// SBOMs. Version is the one in the newer SBOM.
+type LicenseChange struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type,omitempty"`
+	Version string   `json:"version"`
+	Before  []string `json:"before"`
+	After   []string `json:"after"`
+}
+
+// Diff describes how the packages of one SBOM changed into another's.
+type Diff struct {
+	Added          []NormalizedPackage `json:"added"`
+	Removed        []NormalizedPackage `json:"removed"`
+	Upgraded       []VersionChange     `json:"upgraded"`
+	Downgraded     []VersionChange     `json:"downgraded"`
+	LicenseChanged []LicenseChange     `json:"licenseChanged"`
+	Unchanged      int                 `json:"unchanged"`
+}
+
+// Empty reports whether the two SBOMs list the same packages and licenses.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Upgraded) == 0 &&
+		len(d.Downgraded) == 0 && len(d.LicenseChanged) == 0
+}
+
+// DiffPackages compares two package lists. Packages are matched by PURL
+// type, namespace and name (or by package type and name without a PURL),
+// and versions are ordered with the package ecosystem's rules.
+//
+// A package can be installed at several versions. Versions present on both
+// sides are unchanged; the rest are paired newest to newest, and whatever
+// is left over on one side is added or removed.
+func DiffPackages(before, after []NormalizedPackage) Diff {
+	d := Diff{
+		Added:          []NormalizedPackage{},
+		Removed:        []NormalizedPackage{},
+		Upgraded:       []VersionChange{},
+		Downgraded:     []VersionChange{},
+		LicenseChanged: []LicenseChange{},
+	}
+
+	old := groupByIdentity(before)
+	cur := groupByIdentity(after)
+
+	keys := make([]string, 0, len(old)+len(cur))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range cur {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range keys {
+		a, b := old[k], cur[k]
+		if len(a) == 0 {
+			d.Added = append(d.Added, b...)
+			continue
+		}
+		if len(b) == 0 {
+			d.Removed = append(d.Removed, a...)
+			continue
+		}
+
+		cmp := version.For(b[0].Type, b[0].PURL)
+		a, b = matchVersions(&d, a, b)
+
+		newestFirst := func(x, y NormalizedPackage) int { return cmp.Compare(y.Version, x.Version) }
+		slices.SortStableFunc(a, newestFirst)
+		slices.SortStableFunc(b, newestFirst)
+
+		n := min(len(a), len(b))
+		for i := 0; i < n; i++ {
+			c := VersionChange{Name: b[i].Name, Type: b[i].Type, PURL: b[i].PURL, Before: a[i].Version, After: b[i].Version}
+			switch cmp.Compare(a[i].Version, b[i].Version) {
+			case -1:
+				d.Upgraded = append(d.Upgraded, c)
+			case 1:
+				d.Downgraded = append(d.Downgraded, c)
+			default:
+				d.Unchanged++
+			}
+			diffLicenses(&d, a[i], b[i])
+		}
+		d.Removed = append(d.Removed, a[n:]...)
+		d.Added = append(d.Added, b[n:]...)
+	}
+
+	sortPackages(d.Added)
+	sortPackages(d.Removed)
+	sortChanges(d.Upgraded)
+	sortChanges(d.Downgraded)
+	sort.SliceStable(d.LicenseChanged, func(i, j int) bool {
+		x, y := d.LicenseChanged[i], d.LicenseChanged[j]
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		return x.Type < y.Type
+	})
+	return d
+}
+
+// matchVersions counts the versions found on both sides as unchanged and
+// returns what is left of each side.
+func matchVersions(d *Diff, a, b []NormalizedPackage) ([]NormalizedPackage, []NormalizedPackage) {
+	var restA []NormalizedPackage
+	used := make([]bool, len(b))
+	for _, p := range a {
+		found := false
+		for j, q := range b {
+			if !used[j] && p.Version == q.Version {
+				used[j] = true
+				found = true
+				d.Unchanged++
+				diffLicenses(d, p, q)
+				break
+			}
+		}
+		if !found {
+			restA = append(restA, p)
+		}
+	}
+
+	var restB []NormalizedPackage
+	for j, q := range b {
+		if !used[j] {
+			restB = append(restB, q)
+		}
+	}
+	return restA, restB
+}
+
+func diffLicenses(d *Diff, a, b NormalizedPackage) {
+	before, after := licenseSet(a.Licences), licenseSet(b.Licences)
+	if slices.Equal(before, after) {
+		return
+	}
+	d.LicenseChanged = append(d.LicenseChanged, LicenseChange{
+		Name:    b.Name,
+		Type:    b.Type,
+		Version: b.Version,
+		Before:  before,
+		After:   after,
+	})
+}
+
+func licenseSet(ls []string) []string {
+	out := make([]string, 0, len(ls))
+	for _, l := range ls {
+		if l = strings.TrimSpace(l); l != "" && !slices.Contains(out, l) {
+			out = append(out, l)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func groupByIdentity(pkgs []NormalizedPackage) map[string][]NormalizedPackage {
+	out := map[string][]NormalizedPackage{}
+	for _, p := range pkgs {
+		k := packageIdentity(p)
+		out[k] = append(out[k], p)
+	}
+	return out
+}
+
+// packageIdentity names a package without its version. PURL qualifiers are
+// dropped too: they carry the distro release and architecture, which change
+// with a base image bump while the package stays the same.
+func packageIdentity(p NormalizedPackage) string {
+	if u, err := packageurl.FromString(p.PURL); err == nil && p.PURL != "" {
+		return strings.ToLower(u.Type + "/" + u.Namespace + "/" + u.Name)
+	}
+	return strings.ToLower(p.Type + "//" + p.Name)
+}
+
+func sortPackages(pkgs []NormalizedPackage) {
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		a, b := pkgs[i], pkgs[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return version.For(a.Type, a.PURL).Compare(a.Version, b.Version) < 0
+	})
+}
+
+func sortChanges(cs []VersionChange) {
+	sort.SliceStable(cs, func(i, j int) bool {
+		if cs[i].Name != cs[j].Name {
+			return cs[i].Name < cs[j].Name
+		}
+		if cs[i].Type != cs[j].Type {
+			return cs[i].Type < cs[j].Type
+		}
+		return version.For(cs[i].Type, cs[i].PURL).Compare(cs[i].After, cs[j].After) < 0
+	})
+}
@@ -0,0 +1,51 @@
+package sbom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPackages(t *testing.T) {
+	before := []NormalizedPackage{
+		{Name: "libssl3", Version: "3.0.11-1~deb12u2", Type: "deb", PURL: "pkg:deb/debian/libssl3@3.0.11-1~deb12u2?arch=amd64&distro=debian-12", Licences: []string{"Apache-2.0"}},
+		{Name: "zlib1g", Version: "1:1.2.13.dfsg-1", Type: "deb", PURL: "pkg:deb/debian/zlib1g@1:1.2.13.dfsg-1?distro=debian-12"},
+		{Name: "lodash", Version: "4.17.21", Type: "npm", PURL: "pkg:npm/lodash@4.17.21", Licences: []string{"MIT"}},
+		{Name: "lodash", Version: "3.10.1", Type: "npm", PURL: "pkg:npm/lodash@3.10.1"},
+		{Name: "left-pad", Version: "1.3.0", Type: "npm"},
+		{Name: "busybox", Version: "1.36.1", Type: "binary"},
+	}
+	after := []NormalizedPackage{
+		// ~deb12u10 sorts after ~deb12u2 under dpkg rules, not lexically
+		{Name: "libssl3", Version: "3.0.11-1~deb12u10", Type: "deb", PURL: "pkg:deb/debian/libssl3@3.0.11-1~deb12u10?arch=amd64&distro=debian-12", Licences: []string{"Apache-2.0"}},
+		{Name: "zlib1g", Version: "1:1.2.11.dfsg-2", Type: "deb", PURL: "pkg:deb/debian/zlib1g@1:1.2.11.dfsg-2?distro=debian-12"},
+		{Name: "lodash", Version: "4.17.21", Type: "npm", PURL: "pkg:npm/lodash@4.17.21", Licences: []string{"MIT", "CC0-1.0"}},
+		{Name: "left-pad", Version: "1.3.0", Type: "npm"},
+		{Name: "busybox", Version: "1.36.1", Type: "binary"},
+		{Name: "curl", Version: "7.88.1-10", Type: "deb", PURL: "pkg:deb/debian/curl@7.88.1-10"},
+	}
+
+	d := DiffPackages(before, after)
+
+	if len(d.Added) != 1 || d.Added[0].Name != "curl" {
+		t.Errorf("added: %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Name != "lodash" || d.Removed[0].Version != "3.10.1" {
+		t.Errorf("removed: %+v", d.Removed)
+	}
+	if want := []VersionChange{{Name: "libssl3", Type: "deb", PURL: after[0].PURL, Before: "3.0.11-1~deb12u2", After: "3.0.11-1~deb12u10"}}; !reflect.DeepEqual(d.Upgraded, want) {
+		t.Errorf("upgraded: %+v", d.Upgraded)
+	}
+	if len(d.Downgraded) != 1 || d.Downgraded[0].Name != "zlib1g" {
+		t.Errorf("downgraded: %+v", d.Downgraded)
+	}
+	if len(d.LicenseChanged) != 1 || !reflect.DeepEqual(d.LicenseChanged[0].After, []string{"CC0-1.0", "MIT"}) {
+		t.Errorf("license changes: %+v", d.LicenseChanged)
+	}
+	if d.Unchanged != 3 {
+		t.Errorf("expected 3 unchanged, got %d", d.Unchanged)
+	}
+
+	if !DiffPackages(after, after).Empty() {
+		t.Error("expected no changes between identical package lists")
+	}
+}
@@ -0,0 +1,415 @@
+package sbom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// docPackage is what SPDX and CycloneDX documents record about a package
+// that Syft's decoders do not carry over into its model.
+type docPackage struct {
+	name, version, purl  string
+	supplier, originator string
+	digests              []Digest
+}
+
+// enrichFromDocument fills supplier, originator and package digests from
+// the raw document for formats whose decoders drop them. What the document
+// states wins over what was derived from metadata; other formats are left
+// as decoded.
+func enrichFromDocument(pkgs []NormalizedPackage, formatID string, raw []byte) {
+	var entries []docPackage
+	switch formatID {
+	case "spdx-json":
+		entries = spdxPackages(raw)
+	case "spdx-tag-value":
+		entries = spdxTagValuePackages(raw)
+	case "cyclonedx-json":
+		entries = cyclonedxPackages(raw)
+	case "cyclonedx-xml":
+		entries = cyclonedxXMLPackages(raw)
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	byPURL := map[string]docPackage{}
+	byName := map[string]docPackage{}
+	for _, e := range entries {
+		if e.purl != "" {
+			byPURL[e.purl] = e
+		}
+		byName[e.name+"@"+e.version] = e
+	}
+
+	for i := range pkgs {
+		p := &pkgs[i]
+		e, ok := byPURL[p.PURL]
+		if !ok || p.PURL == "" {
+			if e, ok = byName[p.Name+"@"+p.Version]; !ok {
+				continue
+			}
+		}
+		if e.supplier != "" {
+			p.Supplier = e.supplier
+		}
+		if e.originator != "" {
+			p.Originator = e.originator
+		}
+		if len(e.digests) > 0 {
+			p.Digests = dedupeDigests(append(p.Digests, e.digests...))
+		}
+	}
+}
+
+func spdxPackages(raw []byte) []docPackage {
+	var doc struct {
+		Packages []struct {
+			Name       string `json:"name"`
+			Version    string `json:"versionInfo"`
+			Supplier   string `json:"supplier"`
+			Originator string `json:"originator"`
+			Checksums  []struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"checksumValue"`
+			} `json:"checksums"`
+			ExternalRefs []struct {
+				Type    string `json:"referenceType"`
+				Locator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if json.Unmarshal(raw, &doc) != nil {
+		return nil
+	}
+
+	out := make([]docPackage, 0, len(doc.Packages))
+	for _, p := range doc.Packages {
+		e := docPackage{
+			name:       p.Name,
+			version:    p.Version,
+			supplier:   spdxActor(p.Supplier),
+			originator: spdxActor(p.Originator),
+		}
+		for _, r := range p.ExternalRefs {
+			if r.Type == "purl" {
+				e.purl = r.Locator
+				break
+			}
+		}
+		for _, c := range p.Checksums {
+			e.digests = append(e.digests, Digest{Algorithm: normalizeAlgorithm(c.Algorithm), Value: c.Value})
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// spdxTagValuePackages reads the same fields from the tag-value form, where
+// a package runs from its PackageName tag to the next one.
+func spdxTagValuePackages(raw []byte) []docPackage {
+	var out []docPackage
+	var cur *docPackage
+	inText := false
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		// multi-line <text> values may contain anything, tags included
+		if inText {
+			inText = !strings.Contains(line, "</text>")
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "<text>") && !strings.Contains(value, "</text>") {
+			inText = true
+			continue
+		}
+		if key == "PackageName" {
+			out = append(out, docPackage{name: value})
+			cur = &out[len(out)-1]
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		switch key {
+		case "PackageVersion":
+			cur.version = value
+		case "PackageSupplier":
+			cur.supplier = spdxActor(value)
+		case "PackageOriginator":
+			cur.originator = spdxActor(value)
+		case "PackageChecksum":
+			if alg, sum, ok := strings.Cut(value, ":"); ok {
+				cur.digests = append(cur.digests, Digest{Algorithm: normalizeAlgorithm(strings.TrimSpace(alg)), Value: strings.TrimSpace(sum)})
+			}
+		case "ExternalRef":
+			// CATEGORY TYPE LOCATOR
+			if f := strings.Fields(value); len(f) == 3 && f[1] == "purl" && cur.purl == "" {
+				cur.purl = f[2]
+			}
+		}
+	}
+	return out
+}
+
+// spdxActor strips the "Organization: " / "Person: " / "Tool: " prefix of
+// an SPDX actor; NOASSERTION means unknown.
+func spdxActor(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "NOASSERTION" {
+		return ""
+	}
+	if _, name, ok := strings.Cut(s, ":"); ok {
+		return strings.TrimSpace(name)
+	}
+	return s
+}
+
+type cdxComponent struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	PURL      string `json:"purl"`
+	Author    string `json:"author"`
+	Publisher string `json:"publisher"`
+	Supplier  *struct {
+		Name string `json:"name"`
+	} `json:"supplier"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Hashes []struct {
+		Alg     string `json:"alg"`
+		Content string `json:"content"`
+	} `json:"hashes"`
+	Components []cdxComponent `json:"components"`
+}
+
+func cyclonedxPackages(raw []byte) []docPackage {
+	var doc struct {
+		Components []cdxComponent `json:"components"`
+	}
+	if json.Unmarshal(raw, &doc) != nil {
+		return nil
+	}
+
+	var out []docPackage
+	var walk func([]cdxComponent)
+	walk = func(cs []cdxComponent) {
+		for _, c := range cs {
+			e := docPackage{name: c.Name, version: c.Version, purl: c.PURL, originator: c.Author}
+			if e.originator == "" && len(c.Authors) > 0 {
+				e.originator = c.Authors[0].Name
+			}
+			if c.Supplier != nil {
+				e.supplier = c.Supplier.Name
+			}
+			if e.supplier == "" {
+				e.supplier = c.Publisher
+			}
+			for _, h := range c.Hashes {
+				e.digests = append(e.digests, Digest{Algorithm: normalizeAlgorithm(h.Alg), Value: h.Content})
+			}
+			out = append(out, e)
+			walk(c.Components)
+		}
+	}
+	walk(doc.Components)
+	return out
+}
+
+type cdxXMLComponent struct {
+	Name      string   `xml:"name"`
+	Version   string   `xml:"version"`
+	PURL      string   `xml:"purl"`
+	Author    string   `xml:"author"`
+	Publisher string   `xml:"publisher"`
+	Supplier  string   `xml:"supplier>name"`
+	Authors   []string `xml:"authors>author>name"`
+	Hashes    []struct {
+		Alg     string `xml:"alg,attr"`
+		Content string `xml:",chardata"`
+	} `xml:"hashes>hash"`
+	Components []cdxXMLComponent `xml:"components>component"`
+}
+
+// cyclonedxXMLPackages reads the XML form of what cyclonedxPackages reads.
+func cyclonedxXMLPackages(raw []byte) []docPackage {
+	var doc struct {
+		Components []cdxXMLComponent `xml:"components>component"`
+	}
+	if xml.Unmarshal(raw, &doc) != nil {
+		return nil
+	}
+
+	var out []docPackage
+	var walk func([]cdxXMLComponent)
+	walk = func(cs []cdxXMLComponent) {
+		for _, c := range cs {
+			e := docPackage{name: c.Name, version: c.Version, purl: c.PURL, originator: c.Author, supplier: c.Supplier}
+			if e.originator == "" && len(c.Authors) > 0 {
+				e.originator = c.Authors[0]
+			}
+			if e.supplier == "" {
+				e.supplier = c.Publisher
+			}
+			for _, h := range c.Hashes {
+				e.digests = append(e.digests, Digest{Algorithm: normalizeAlgorithm(h.Alg), Value: strings.TrimSpace(h.Content)})
+			}
+			out = append(out, e)
+			walk(c.Components)
+		}
+	}
+	walk(doc.Components)
+	return out
+}
+
+// normalizeAlgorithm maps SPDX (SHA256) and CycloneDX (SHA-256) spellings
+// to Syft's (sha256).
+func normalizeAlgorithm(a string) string {
+	return strings.ToLower(strings.ReplaceAll(a, "-", ""))
+}
+
+// docInfo is what a document says about itself: who or what produced it
+// and when.
+type docInfo struct {
+	Authors []string
+	Created time.Time
+}
+
+// documentInfo reads the creators and creation time from the raw document.
+// Syft's decoders keep neither. Tools count as authors, as they do in SPDX
+// creationInfo. syft-json has no creation time.
+func documentInfo(formatID string, raw []byte) docInfo {
+	var info docInfo
+	switch formatID {
+	case "spdx-json":
+		var doc struct {
+			CreationInfo struct {
+				Creators []string `json:"creators"`
+				Created  string   `json:"created"`
+			} `json:"creationInfo"`
+		}
+		if json.Unmarshal(raw, &doc) == nil {
+			for _, c := range doc.CreationInfo.Creators {
+				info.Authors = appendNonEmpty(info.Authors, spdxActor(c))
+			}
+			info.Created = parseTimestamp(doc.CreationInfo.Created)
+		}
+	case "spdx-tag-value":
+		for _, line := range strings.Split(string(raw), "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "Creator":
+				info.Authors = appendNonEmpty(info.Authors, spdxActor(value))
+			case "Created":
+				info.Created = parseTimestamp(strings.TrimSpace(value))
+			}
+		}
+	case "cyclonedx-json":
+		var doc struct {
+			Metadata struct {
+				Timestamp string          `json:"timestamp"`
+				Tools     json.RawMessage `json:"tools"`
+				Authors   []struct {
+					Name string `json:"name"`
+				} `json:"authors"`
+				Manufacture *struct {
+					Name string `json:"name"`
+				} `json:"manufacture"`
+				Supplier *struct {
+					Name string `json:"name"`
+				} `json:"supplier"`
+			} `json:"metadata"`
+		}
+		if json.Unmarshal(raw, &doc) == nil {
+			m := doc.Metadata
+			for _, a := range m.Authors {
+				info.Authors = appendNonEmpty(info.Authors, a.Name)
+			}
+			if m.Manufacture != nil {
+				info.Authors = appendNonEmpty(info.Authors, m.Manufacture.Name)
+			}
+			if m.Supplier != nil {
+				info.Authors = appendNonEmpty(info.Authors, m.Supplier.Name)
+			}
+			for _, t := range cyclonedxTools(m.Tools) {
+				info.Authors = appendNonEmpty(info.Authors, t)
+			}
+			info.Created = parseTimestamp(m.Timestamp)
+		}
+	case "cyclonedx-xml":
+		var doc struct {
+			Metadata struct {
+				Timestamp  string   `xml:"timestamp"`
+				Authors    []string `xml:"authors>author>name"`
+				Tools      []string `xml:"tools>tool>name"`
+				Components []string `xml:"tools>components>component>name"`
+			} `xml:"metadata"`
+		}
+		if xml.Unmarshal(raw, &doc) == nil {
+			m := doc.Metadata
+			for _, a := range append(append(m.Authors, m.Tools...), m.Components...) {
+				info.Authors = appendNonEmpty(info.Authors, a)
+			}
+			info.Created = parseTimestamp(m.Timestamp)
+		}
+	case "syft-json":
+		var doc struct {
+			Descriptor struct {
+				Name string `json:"name"`
+			} `json:"descriptor"`
+		}
+		if json.Unmarshal(raw, &doc) == nil {
+			info.Authors = appendNonEmpty(info.Authors, doc.Descriptor.Name)
+		}
+	}
+	return info
+}
+
+// cyclonedxTools reads metadata.tools in both its 1.4 form (a list of
+// tools) and its 1.5 form (an object of components and services).
+func cyclonedxTools(raw json.RawMessage) []string {
+	type named struct {
+		Name string `json:"name"`
+	}
+	var list []named
+	if json.Unmarshal(raw, &list) != nil {
+		var obj struct {
+			Components []named `json:"components"`
+			Services   []named `json:"services"`
+		}
+		if json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		list = append(obj.Components, obj.Services...)
+	}
+	out := make([]string, 0, len(list))
+	for _, t := range list {
+		out = append(out, t.Name)
+	}
+	return out
+}
+
+func parseTimestamp(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func appendNonEmpty(ss []string, s string) []string {
+	if s = strings.TrimSpace(s); s == "" {
+		return ss
+	}
+	return append(ss, s)
+}
@@ -0,0 +1,104 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/format/cyclonedxjson"
+	"github.com/anchore/syft/syft/format/cyclonedxxml"
+	"github.com/anchore/syft/syft/format/spdxjson"
+	"github.com/anchore/syft/syft/format/spdxtagvalue"
+	"github.com/anchore/syft/syft/format/syftjson"
+	syftsobm "github.com/anchore/syft/syft/sbom"
+)
+
+// Output formats accepted by Encode. The names match the syft CLI's -o values.
+const (
+	FormatCycloneDXJSON = "cyclonedx-json"
+	FormatCycloneDXXML  = "cyclonedx-xml"
+	FormatSPDXJSON      = "spdx-json"
+	FormatSPDXTagValue  = "spdx-tag-value"
+	FormatSyftJSON      = "syft-json"
+)
+
+var encoders = map[string]func() (syftsobm.FormatEncoder, error){
+	FormatCycloneDXJSON: func() (syftsobm.FormatEncoder, error) {
+		cfg := cyclonedxjson.DefaultEncoderConfig()
+		cfg.Pretty = true
+		return cyclonedxjson.NewFormatEncoderWithConfig(cfg)
+	},
+	FormatCycloneDXXML: func() (syftsobm.FormatEncoder, error) {
+		cfg := cyclonedxxml.DefaultEncoderConfig()
+		cfg.Pretty = true
+		return cyclonedxxml.NewFormatEncoderWithConfig(cfg)
+	},
+	FormatSPDXJSON: func() (syftsobm.FormatEncoder, error) {
+		cfg := spdxjson.DefaultEncoderConfig()
+		cfg.Pretty = true
+		return spdxjson.NewFormatEncoderWithConfig(cfg)
+	},
+	FormatSPDXTagValue: func() (syftsobm.FormatEncoder, error) {
+		return spdxtagvalue.NewFormatEncoderWithConfig(spdxtagvalue.DefaultEncoderConfig())
+	},
+	FormatSyftJSON: func() (syftsobm.FormatEncoder, error) {
+		return syftjson.NewFormatEncoderWithConfig(syftjson.EncoderConfig{Pretty: true})
+	},
+}
+
+// ValidateFormat reports an error for formats Encode does not accept.
+func ValidateFormat(format string) error {
+	if _, ok := encoders[strings.ToLower(format)]; !ok {
+		return fmt.Errorf("sbom encode: unsupported format %q (use %s)", format, strings.Join(EncodeFormats(), ", "))
+	}
+	return nil
+}
+
+// EncodeFormats lists the formats Encode accepts.
+func EncodeFormats() []string {
+	out := make([]string, 0, len(encoders))
+	for f := range encoders {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Encode writes doc in the named format, using the latest spec version
+// Syft supports for it. Documents without a tool descriptor (decoded from
+// formats that do not carry one) are credited to provavalidator.
+func Encode(w io.Writer, doc *syftsobm.SBOM, format string) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+	enc, err := encoders[strings.ToLower(format)]()
+	if err != nil {
+		return fmt.Errorf("sbom encode: %w", err)
+	}
+
+	out := *doc
+	if out.Descriptor.Name == "" {
+		out.Descriptor = syftsobm.Descriptor{Name: "provavalidator", Version: toolVersion()}
+	}
+	if out.Source.Name == "" {
+		// SPDX writes the source as the document's root package; without a
+		// name the tag-value output cannot be parsed again
+		out.Source.Name = "unknown"
+	}
+	if err := enc.Encode(w, out); err != nil {
+		return fmt.Errorf("sbom encode: %s: %w", format, err)
+	}
+	return nil
+}
+
+// toolVersion reports this binary's main module version, "devel" for
+// local builds.
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" || info.Main.Version == "(devel)" {
+		return "devel"
+	}
+	return info.Main.Version
+}
@@ -0,0 +1,79 @@
+package sbom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestEncode_RoundTrip(t *testing.T) {
+	in, err := DecodeFile("testdata/cyclonedx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SPDX only drops its root package again for image and file sources
+	doc := *in.SBOM
+	doc.Source = source.Description{
+		Name:     "example.com/app",
+		Version:  "1.0",
+		Metadata: source.ImageMetadata{UserInput: "example.com/app:1.0"},
+	}
+
+	for _, format := range EncodeFormats() {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, &doc, format); err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			out, err := DecodeBytes(buf.Bytes())
+			if format == FormatSyftJSON && !syftJSONDecodable {
+				if err == nil {
+					t.Fatal("expected syft-json decoding to be refused")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode %s output: %v", format, err)
+			}
+			if out.FormatID != format {
+				t.Errorf("decoded as %s", out.FormatID)
+			}
+			pkgs := NormalizePackage(out.SBOM)
+			if len(pkgs) != 1 || pkgs[0].Name != "openssl" || pkgs[0].Version != "3.0.2" || pkgs[0].PURL != "pkg:deb/debian/openssl@3.0.2" {
+				t.Errorf("unexpected packages after round trip: %+v", pkgs)
+			}
+		})
+	}
+
+	if err := Encode(&bytes.Buffer{}, in.SBOM, "table"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestFilterPackages(t *testing.T) {
+	pkgs := []NormalizedPackage{
+		{Name: "openssl", Type: "deb", Licences: []string{"Apache-2.0"}},
+		{Name: "libssl3", Type: "deb", Licences: []string{"Apache-2.0"}},
+		{Name: "left-pad", Type: "npm", Licences: []string{"WTFPL"}},
+		{Name: "golang.org/x/net", Type: "go-module"},
+	}
+
+	tests := []struct {
+		name   string
+		filter PackageFilter
+		want   int
+	}{
+		{"none", PackageFilter{}, 4},
+		{"type", PackageFilter{Types: []string{"deb", "NPM"}}, 3},
+		{"substring", PackageFilter{Name: "SSL"}, 2},
+		{"glob", PackageFilter{Name: "lib*"}, 1},
+		{"license", PackageFilter{License: "apache"}, 2},
+		{"combined", PackageFilter{Types: []string{"deb"}, Name: "open", License: "apache"}, 1},
+	}
+	for _, tt := range tests {
+		if got := FilterPackages(pkgs, tt.filter); len(got) != tt.want {
+			t.Errorf("%s: got %d packages, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
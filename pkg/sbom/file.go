@@ -0,0 +1,58 @@
+package sbom
+
+import (
+	"fmt"
+	"os"
+
+	syftsobm "github.com/anchore/syft/syft/sbom"
+)
+
+// LoadFile reads an SBOM document from disk in any format Decode supports.
+func LoadFile(path string) (*ResolvedSBOM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("sbom: read %s: %w", path, err)
+	}
+	s, err := resolveBytes(SourceFile, data)
+	if err != nil {
+		return nil, fmt.Errorf("sbom: %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// resolveBytes decodes an SBOM document obtained from src.
+func resolveBytes(src SourceType, data []byte) (*ResolvedSBOM, error) {
+	dec, err := DecodeBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	pkgs := NormalizePackage(dec.SBOM)
+	enrichFromDocument(pkgs, dec.FormatID, data)
+
+	return &ResolvedSBOM{
+		Source:        src,
+		Format:        dec.FormatID,
+		Packages:      pkgs,
+		Relationships: RelationshipsFromSBOM(dec.SBOM),
+		Distro:        DistroFromSBOM(dec.SBOM),
+		ImageDigest:   ImageDigestFromSBOM(dec.SBOM),
+		RawPayload:    data,
+		doc:           dec.SBOM,
+	}, nil
+}
+
+// Document returns Syft's model of the SBOM, for re-encoding in another
+// format. SBOMs from the cache only have RawPayload, which is decoded.
+func (r *ResolvedSBOM) Document() (*syftsobm.SBOM, error) {
+	if r.doc != nil {
+		return r.doc, nil
+	}
+	if len(r.RawPayload) == 0 {
+		return nil, fmt.Errorf("sbom: no document payload for %s SBOM", r.Source)
+	}
+	dec, err := DecodeBytes(r.RawPayload)
+	if err != nil {
+		return nil, err
+	}
+	return dec.SBOM, nil
+}
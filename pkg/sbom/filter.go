@@ -0,0 +1,67 @@
+package sbom
+
+import (
+	"path"
+	"strings"
+)
+
+// PackageFilter selects packages for display. Empty fields match everything.
+type PackageFilter struct {
+	// Types are package types (deb, npm, go-module, ...), matched exactly.
+	Types []string
+	// Name is a case-insensitive substring, or a glob when it contains *, ? or [.
+	Name string
+	// License is a case-insensitive substring of any of the package's licenses.
+	License string
+}
+
+func FilterPackages(pkgs []NormalizedPackage, f PackageFilter) []NormalizedPackage {
+	out := make([]NormalizedPackage, 0, len(pkgs))
+	for _, p := range pkgs {
+		if f.matches(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (f PackageFilter) matches(p NormalizedPackage) bool {
+	if len(f.Types) > 0 {
+		ok := false
+		for _, t := range f.Types {
+			if strings.EqualFold(t, p.Type) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if f.Name != "" {
+		name, pattern := strings.ToLower(p.Name), strings.ToLower(f.Name)
+		if strings.ContainsAny(pattern, "*?[") {
+			if ok, _ := path.Match(pattern, name); !ok {
+				return false
+			}
+		} else if !strings.Contains(name, pattern) {
+			return false
+		}
+	}
+
+	if f.License != "" {
+		want := strings.ToLower(f.License)
+		ok := false
+		for _, l := range p.Licences {
+			if strings.Contains(strings.ToLower(l), want) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
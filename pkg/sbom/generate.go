@@ -7,27 +7,87 @@ import (
 	"os/exec"
 
 	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/format/syftjson"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/registry"
 	_ "modernc.org/sqlite"
 )
 
+// sbomConfig is the cataloger configuration for generated SBOMs. It is part
+// of the SBOM cache key, so every change here invalidates cached entries.
+func sbomConfig() *syft.CreateSBOMConfig {
+	return syft.DefaultCreateSBOMConfig()
+}
+
+// generateSBOMForImageCached reuses a generated SBOM when the image digest,
+// Syft version and cataloger config all match. Images whose digest cannot
+// be resolved (local daemon images, unreachable registries) are generated
+// without caching.
+func generateSBOMForImageCached(ctx context.Context, imageRef string) (*ResolvedSBOM, error) {
+	digest, err := registry.ResolveDigest(ctx, imageRef)
+	if err != nil {
+		return generateSBOMForImage(ctx, imageRef)
+	}
+	cache, err := OpenCache("")
+	if err != nil {
+		return generateSBOMForImage(ctx, imageRef)
+	}
+	tool, err := toolKey(sbomConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, ok := cache.Get(digest, tool); ok {
+		return cached, nil
+	}
+
+	res, err := generateSBOMForImage(ctx, imageRef)
+	if err != nil {
+		return nil, err
+	}
+	// best effort: a failed write only costs the next run a regeneration
+	_ = cache.Put(digest, tool, res)
+	return res, nil
+}
+
 func generateSBOMForImage(ctx context.Context, imageRef string) (*ResolvedSBOM, error) {
+	return generateSBOM(ctx, imageRef)
+}
+
+// generateSBOM catalogs input with Syft. sources narrows the Syft source
+// providers tried (e.g. "oci-dir"); by default Syft guesses from the input.
+func generateSBOM(ctx context.Context, input string, sources ...string) (*ResolvedSBOM, error) {
 	srcCfg := syft.DefaultGetSourceConfig()
+	if len(sources) > 0 {
+		srcCfg = srcCfg.WithSources(sources...)
+	}
 
-	src, err := syft.GetSource(ctx, imageRef, srcCfg)
+	src, err := syft.GetSource(ctx, input, srcCfg)
 	if err != nil {
 		return nil, fmt.Errorf("get source: %w", err)
 	}
 	defer src.Close()
-	cfg := syft.DefaultCreateSBOMConfig()
+	cfg := sbomConfig()
 
 	sbomResult, err := syft.CreateSBOM(ctx, src, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("create SBOM: %w", err)
 	}
+
+	raw, err := format.Encode(*sbomResult, syftjson.NewFormatEncoder())
+	if err != nil {
+		return nil, fmt.Errorf("encode SBOM: %w", err)
+	}
+
 	res := &ResolvedSBOM{
-		Source:   SourceGenerated,
-		Format:   "syft-json",
-		Packages: NormalizePackage(sbomResult),
+		Source:        SourceGenerated,
+		Format:        string(syftjson.ID),
+		Packages:      NormalizePackage(sbomResult),
+		Relationships: RelationshipsFromSBOM(sbomResult),
+		Distro:        DistroFromSBOM(sbomResult),
+		ImageDigest:   ImageDigestFromSBOM(sbomResult),
+		RawPayload:    raw,
+		doc:           sbomResult,
 	}
 	return res, nil
 }
@@ -52,11 +112,17 @@ func generateSBOMForImageCLI(ctx context.Context, imageRef string) (*ResolvedSBO
 	if err != nil {
 		return nil, fmt.Errorf("decode generated SBOM: %w", err)
 	}
+	pkgs := NormalizePackage(decoded.SBOM)
+	enrichFromDocument(pkgs, decoded.FormatID, out.Bytes())
 
 	return &ResolvedSBOM{
-		Source:     SourceGenerated,
-		Format:     decoded.FormatID,
-		Packages:   NormalizePackage(decoded.SBOM),
-		RawPayload: out.Bytes(),
+		Source:        SourceGenerated,
+		Format:        decoded.FormatID,
+		Packages:      pkgs,
+		Relationships: RelationshipsFromSBOM(decoded.SBOM),
+		Distro:        DistroFromSBOM(decoded.SBOM),
+		ImageDigest:   ImageDigestFromSBOM(decoded.SBOM),
+		RawPayload:    out.Bytes(),
+		doc:           decoded.SBOM,
 	}, nil
 }
@@ -0,0 +1,265 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+	syftsobm "github.com/anchore/syft/syft/sbom"
+)
+
+type RelationshipType string
+
+const (
+	// DependsOn: From needs To at runtime or build time.
+	DependsOn RelationshipType = "depends-on"
+	// Contains: To is bundled inside From, e.g. a jar in a war or a Python
+	// package installed by a deb.
+	Contains RelationshipType = "contains"
+	// EvidentBy: From was cataloged from the file To.
+	EvidentBy RelationshipType = "evident-by"
+)
+
+// Relationship is an edge of the package graph. From and To are package
+// IDs, except for evident-by where To is a file path.
+type Relationship struct {
+	From string           `json:"from"`
+	To   string           `json:"to"`
+	Type RelationshipType `json:"type"`
+}
+
+// stablePackageID derives a package's ID from the fields that identify it,
+// so the same package gets the same ID on every run and, as far as the
+// fields survive conversion, in every format.
+func stablePackageID(p NormalizedPackage) string {
+	h := sha256.New()
+	for _, s := range append([]string{p.Type, p.Name, p.Version, p.PURL}, p.Locations...) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return "pkg-" + hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// RelationshipsFromSBOM maps Syft's relationships onto normalized package
+// IDs. Syft records dependencies as dependency-of (dependency to dependent);
+// they are flipped to depends-on. Package-to-file containment is dropped:
+// evident-by already says which files matter.
+func RelationshipsFromSBOM(doc *syftsobm.SBOM) []Relationship {
+	if doc == nil {
+		return nil
+	}
+
+	ids := map[artifact.ID]string{}
+	for _, p := range doc.Artifacts.Packages.Sorted() {
+		ids[p.ID()] = normalizeOne(p, nil).ID
+	}
+	pkgID := func(a artifact.Identifiable) (string, bool) {
+		if _, ok := a.(pkg.Package); !ok {
+			if _, ok := a.(*pkg.Package); !ok {
+				return "", false
+			}
+		}
+		id, ok := ids[a.ID()]
+		return id, ok
+	}
+
+	seen := map[Relationship]struct{}{}
+	out := make([]Relationship, 0, len(doc.Relationships))
+	add := func(r Relationship) {
+		if _, ok := seen[r]; ok || r.From == r.To {
+			return
+		}
+		seen[r] = struct{}{}
+		out = append(out, r)
+	}
+
+	for _, r := range doc.Relationships {
+		from, fromPkg := pkgID(r.From)
+		if !fromPkg {
+			continue
+		}
+		switch r.Type {
+		case artifact.DependencyOfRelationship:
+			if to, ok := pkgID(r.To); ok {
+				add(Relationship{From: to, To: from, Type: DependsOn})
+			}
+		case artifact.ContainsRelationship, artifact.OwnershipByFileOverlapRelationship:
+			if to, ok := pkgID(r.To); ok {
+				add(Relationship{From: from, To: to, Type: Contains})
+			}
+		case artifact.EvidentByRelationship:
+			if path := filePath(r.To); path != "" {
+				add(Relationship{From: from, To: path, Type: EvidentBy})
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.To < b.To
+	})
+	return out
+}
+
+func filePath(a artifact.Identifiable) string {
+	switch f := a.(type) {
+	case file.Coordinates:
+		return f.RealPath
+	case file.Location:
+		return f.RealPath
+	case *file.Location:
+		return f.RealPath
+	}
+	return ""
+}
+
+// Graph indexes packages and their relationships.
+type Graph struct {
+	Packages      []NormalizedPackage `json:"packages"`
+	Relationships []Relationship      `json:"relationships"`
+
+	byID    map[string]int
+	parents map[string][]string // package ID -> packages that depend on or contain it
+}
+
+// NewGraph indexes pkgs and rels. Relationships naming unknown packages are
+// kept but cannot be followed.
+func NewGraph(pkgs []NormalizedPackage, rels []Relationship) *Graph {
+	g := &Graph{
+		Packages:      pkgs,
+		Relationships: rels,
+		byID:          make(map[string]int, len(pkgs)),
+		parents:       map[string][]string{},
+	}
+	for i, p := range pkgs {
+		g.byID[p.ID] = i
+	}
+	for _, r := range rels {
+		if r.Type == DependsOn || r.Type == Contains {
+			g.parents[r.To] = append(g.parents[r.To], r.From)
+		}
+	}
+	return g
+}
+
+// Graph returns the package graph of the SBOM.
+func (r *ResolvedSBOM) Graph() *Graph {
+	return NewGraph(r.Packages, r.Relationships)
+}
+
+// Package looks a package up by ID.
+func (g *Graph) Package(id string) (NormalizedPackage, bool) {
+	i, ok := g.byID[id]
+	if !ok {
+		return NormalizedPackage{}, false
+	}
+	return g.Packages[i], true
+}
+
+// Bounds for path searches in graphs with deep chains or many diamonds.
+const (
+	maxPathDepth = 16
+	maxPathSteps = 10000
+)
+
+// Paths returns up to limit chains of packages through which id is pulled
+// in, shortest first. Each chain starts at a package nothing depends on or
+// contains and ends at a direct parent of id. A package with no parents has
+// no paths.
+func (g *Graph) Paths(id string, limit int) [][]NormalizedPackage {
+	if limit <= 0 || len(g.parents[id]) == 0 {
+		return nil
+	}
+
+	// breadth-first over partial paths, walking up from id
+	type partial []string
+	queue := []partial{{id}}
+	var out [][]NormalizedPackage
+	for steps := 0; len(queue) > 0 && len(out) < limit && steps < maxPathSteps; steps++ {
+		path := queue[0]
+		queue = queue[1:]
+		head := path[len(path)-1]
+
+		extended := false
+		if len(path) <= maxPathDepth {
+			for _, p := range g.parents[head] {
+				if containsID(path, p) {
+					continue // cycle
+				}
+				next := make(partial, len(path), len(path)+1)
+				copy(next, path)
+				queue = append(queue, append(next, p))
+				extended = true
+			}
+		}
+		if !extended {
+			out = append(out, g.chain(path[1:]))
+		}
+	}
+	return out
+}
+
+// chain resolves a path walked upwards into packages, root first.
+func (g *Graph) chain(ids []string) []NormalizedPackage {
+	out := make([]NormalizedPackage, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		if p, ok := g.Package(ids[i]); ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func containsID(ids []string, id string) bool {
+	for _, s := range ids {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteDOT renders the graph for Graphviz. Packages are labelled name and
+// version; files named by evident-by edges become box nodes.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph sbom {\n  rankdir=LR;\n  node [shape=ellipse];"); err != nil {
+		return err
+	}
+	for _, p := range g.Packages {
+		label := p.Name
+		if p.Version != "" {
+			label += "\n" + p.Version
+		}
+		fmt.Fprintf(w, "  %s [label=%s];\n", strconv.Quote(p.ID), strconv.Quote(label))
+	}
+
+	files := map[string]bool{}
+	for _, r := range g.Relationships {
+		if r.Type == EvidentBy && !files[r.To] {
+			files[r.To] = true
+			fmt.Fprintf(w, "  %s [shape=box];\n", strconv.Quote(r.To))
+		}
+		style := ""
+		switch r.Type {
+		case Contains:
+			style = ", style=bold"
+		case EvidentBy:
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(w, "  %s -> %s [label=%q%s];\n", strconv.Quote(r.From), strconv.Quote(r.To), r.Type, style)
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
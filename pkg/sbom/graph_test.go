@@ -0,0 +1,94 @@
+package sbom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+	syftsbom "github.com/anchore/syft/syft/sbom"
+)
+
+func TestRelationshipsFromSBOM(t *testing.T) {
+	app := pkg.Package{Name: "app", Version: "1.0.0", Type: pkg.NpmPkg, PURL: "pkg:npm/app@1.0.0"}
+	express := pkg.Package{Name: "express", Version: "4.18.2", Type: pkg.NpmPkg, PURL: "pkg:npm/express@4.18.2"}
+	qs := pkg.Package{Name: "qs", Version: "6.11.0", Type: pkg.NpmPkg, PURL: "pkg:npm/qs@6.11.0"}
+	for _, p := range []*pkg.Package{&app, &express, &qs} {
+		p.SetID()
+	}
+	lock := file.NewCoordinates("/srv/app/package-lock.json", "")
+
+	doc := &syftsbom.SBOM{
+		Artifacts: syftsbom.Artifacts{Packages: pkg.NewCollection(app, express, qs)},
+		Relationships: []artifact.Relationship{
+			// Syft points dependency-of from the dependency to the dependent
+			{From: express, To: app, Type: artifact.DependencyOfRelationship},
+			{From: qs, To: express, Type: artifact.DependencyOfRelationship},
+			{From: app, To: lock, Type: artifact.EvidentByRelationship},
+			{From: app, To: lock, Type: artifact.ContainsRelationship}, // package to file: dropped
+		},
+	}
+
+	pkgs := NormalizePackage(doc)
+	rels := RelationshipsFromSBOM(doc)
+	g := NewGraph(pkgs, rels)
+
+	id := map[string]string{}
+	for _, p := range pkgs {
+		if p.ID == "" {
+			t.Fatalf("%s has no ID", p.Name)
+		}
+		id[p.Name] = p.ID
+	}
+	if again := NormalizePackage(doc); again[0].ID != pkgs[0].ID {
+		t.Fatal("package IDs are not stable")
+	}
+
+	want := map[Relationship]bool{
+		{From: id["app"], To: id["express"], Type: DependsOn}:                true,
+		{From: id["express"], To: id["qs"], Type: DependsOn}:                 true,
+		{From: id["app"], To: "/srv/app/package-lock.json", Type: EvidentBy}: true,
+	}
+	if len(rels) != len(want) {
+		t.Fatalf("unexpected relationships: %+v", rels)
+	}
+	for _, r := range rels {
+		if !want[r] {
+			t.Errorf("unexpected relationship %+v", r)
+		}
+	}
+
+	paths := g.Paths(id["qs"], 3)
+	if len(paths) != 1 || len(paths[0]) != 2 || paths[0][0].Name != "app" || paths[0][1].Name != "express" {
+		t.Fatalf("unexpected paths to qs: %+v", paths)
+	}
+	if paths := g.Paths(id["app"], 3); paths != nil {
+		t.Fatalf("expected no paths to a root package, got %+v", paths)
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, s := range []string{"digraph sbom {", `"` + id["app"] + `" -> "` + id["express"] + `" [label="depends-on"]`, `"/srv/app/package-lock.json" [shape=box]`} {
+		if !strings.Contains(dot, s) {
+			t.Errorf("DOT output missing %q:\n%s", s, dot)
+		}
+	}
+}
+
+func TestGraphPaths_Cycle(t *testing.T) {
+	pkgs := []NormalizedPackage{{ID: "a", Name: "a"}, {ID: "b", Name: "b"}, {ID: "c", Name: "c"}}
+	g := NewGraph(pkgs, []Relationship{
+		{From: "a", To: "b", Type: DependsOn},
+		{From: "b", To: "a", Type: DependsOn},
+		{From: "b", To: "c", Type: DependsOn},
+	})
+	paths := g.Paths("c", 3)
+	if len(paths) != 1 || len(paths[0]) != 2 || paths[0][0].Name != "a" {
+		t.Fatalf("unexpected paths: %+v", paths)
+	}
+}
@@ -0,0 +1,109 @@
+package sbom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// rootfs is a minimal Debian filesystem with one installed package.
+var rootfs = map[string][]byte{
+	"etc/os-release": []byte("ID=debian\nVERSION_ID=\"12\"\n"),
+	"var/lib/dpkg/status": []byte(`Package: openssl
+Status: install ok installed
+Architecture: amd64
+Version: 3.0.11-1~deb12u2
+
+`),
+}
+
+func TestExtractSBOM_LocalSources(t *testing.T) {
+	tmp := t.TempDir()
+
+	dir := filepath.Join(tmp, "rootfs")
+	for p, b := range rootfs {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(p)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, p), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	layer, err := crane.Layer(rootfs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(tmp, "image.tar")
+	if err := tarball.WriteToFile(archive, name.MustParseReference("example.com/app:1.0"), img); err != nil {
+		t.Fatal(err)
+	}
+	oci, err := layout.Write(filepath.Join(tmp, "oci"), empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := oci.AppendImage(img); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, src := range []string{
+		"dir:" + dir,
+		"docker-archive:" + archive,
+		"oci-layout:" + string(oci),
+		"sbom:testdata/cyclonedx.json",
+	} {
+		scheme, _, ok := SplitSource(src)
+		if !ok {
+			t.Fatalf("%s not recognised as a local source", src)
+		}
+		t.Run(scheme, func(t *testing.T) {
+			s, err := ExtractSBOMWithOptions(context.Background(), src, ExtractOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var found bool
+			for _, p := range s.Packages {
+				found = found || p.Name == "openssl"
+			}
+			if !found {
+				t.Fatalf("openssl not cataloged from %s: %+v", src, s.Packages)
+			}
+			if image := scheme == SchemeDockerArchive || scheme == SchemeOCILayout; image != (s.ImageDigest != "") {
+				t.Errorf("unexpected image digest %q", s.ImageDigest)
+			}
+		})
+	}
+}
+
+func TestSplitSource(t *testing.T) {
+	tests := []struct {
+		in, scheme, path string
+		ok               bool
+	}{
+		{"dir:./rootfs", SchemeDir, "./rootfs", true},
+		{"sbom:bom.json", SchemeSBOM, "bom.json", true},
+		{"oci-layout:/tmp/oci", SchemeOCILayout, "/tmp/oci", true},
+		{"localhost:5000/app", "", "", false},
+		{"nginx:1.25", "", "", false},
+		{"dir:", "", "", false},
+	}
+	for _, tt := range tests {
+		scheme, path, ok := SplitSource(tt.in)
+		if scheme != tt.scheme || path != tt.path || ok != tt.ok {
+			t.Errorf("SplitSource(%q) = %q, %q, %v", tt.in, scheme, path, ok)
+		}
+	}
+}
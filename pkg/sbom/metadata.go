@@ -0,0 +1,188 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// Digest is a checksum of a file the package was found by, or of the
+// package itself when Path is empty (SPDX and CycloneDX package hashes).
+type Digest struct {
+	Path      string `json:"path,omitempty"`
+	Algorithm string `json:"algorithm"` // lower case, e.g. "sha256"
+	Value     string `json:"value"`
+}
+
+// Upstream is the distro source package a binary package was built from,
+// e.g. openssl for libssl3. Distro advisories are often filed against it.
+type Upstream struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+func normalizeCPEs(p pkg.Package) []string {
+	if len(p.CPEs) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(p.CPEs))
+	for _, c := range p.CPEs {
+		if s := c.Attributes.String(); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// evidenceDigests returns the digests Syft computed for the files that are
+// primary evidence of the package (all its locations when none is marked),
+// plus archive digests recorded in Java metadata.
+func evidenceDigests(p pkg.Package, digests map[file.Coordinates][]file.Digest) []Digest {
+	locs := p.Locations.ToSlice()
+	var primary []file.Location
+	for _, l := range locs {
+		if l.Annotations[pkg.EvidenceAnnotationKey] == pkg.PrimaryEvidenceAnnotation {
+			primary = append(primary, l)
+		}
+	}
+	if len(primary) > 0 {
+		locs = primary
+	}
+
+	var out []Digest
+	for _, l := range locs {
+		for _, d := range digests[l.Coordinates] {
+			out = append(out, Digest{Path: l.RealPath, Algorithm: strings.ToLower(d.Algorithm), Value: d.Value})
+		}
+	}
+	if m, ok := p.Metadata.(pkg.JavaArchive); ok {
+		path := ""
+		if len(locs) > 0 {
+			path = locs[0].RealPath
+		}
+		for _, d := range m.ArchiveDigests {
+			out = append(out, Digest{Path: path, Algorithm: strings.ToLower(d.Algorithm), Value: d.Value})
+		}
+	}
+	return dedupeDigests(out)
+}
+
+func dedupeDigests(ds []Digest) []Digest {
+	if len(ds) == 0 {
+		return nil
+	}
+	seen := map[Digest]struct{}{}
+	out := ds[:0]
+	for _, d := range ds {
+		if _, ok := seen[d]; ok || d.Value == "" {
+			continue
+		}
+		seen[d] = struct{}{}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Algorithm < out[j].Algorithm
+	})
+	return out
+}
+
+// supplierOriginator reads who made and who distributed a package from
+// cataloger metadata, following Syft's SPDX mapping: the originator is the
+// author or maintainer, and the supplier is the same unless the metadata
+// names a vendor.
+func supplierOriginator(p pkg.Package) (supplier, originator string) {
+	switch m := p.Metadata.(type) {
+	case pkg.DpkgDBEntry:
+		originator = m.Maintainer
+	case pkg.DpkgArchiveEntry:
+		originator = m.Maintainer
+	case pkg.ApkDBEntry:
+		originator = m.Maintainer
+	case pkg.RpmDBEntry:
+		supplier = m.Vendor
+	case pkg.RpmArchive:
+		supplier = m.Vendor
+	case pkg.NpmPackage:
+		originator = m.Author
+	case pkg.PythonPackage:
+		originator = m.Author
+	case pkg.RubyGemspec:
+		if len(m.Authors) > 0 {
+			originator = m.Authors[0]
+		}
+	case pkg.JavaArchive:
+		if m.Manifest != nil {
+			supplier = m.Manifest.Main.MustGet("Specification-Vendor")
+			if supplier == "" {
+				supplier = m.Manifest.Main.MustGet("Implementation-Vendor")
+			}
+		}
+	}
+
+	supplier, originator = strings.TrimSpace(supplier), strings.TrimSpace(originator)
+	if supplier == "" {
+		supplier = originator
+	}
+	if originator == "" {
+		originator = supplier
+	}
+	return supplier, originator
+}
+
+// upstreamOf returns the source package from distro metadata, falling back
+// to the PURL's upstream qualifier, which survives SPDX and CycloneDX.
+func upstreamOf(p pkg.Package) *Upstream {
+	var u Upstream
+	switch m := p.Metadata.(type) {
+	case pkg.DpkgDBEntry:
+		u = Upstream{Name: m.Source, Version: m.SourceVersion}
+	case pkg.RpmDBEntry:
+		u = parseSourceRPM(m.SourceRpm)
+	case pkg.ApkDBEntry:
+		u = Upstream{Name: m.OriginPackage}
+	}
+	if u.Name == "" {
+		u = upstreamFromPURL(p.PURL)
+	}
+	if u.Name == "" || (u.Name == p.Name && (u.Version == "" || u.Version == p.Version)) {
+		return nil
+	}
+	return &u
+}
+
+func upstreamFromPURL(purl string) Upstream {
+	u, err := packageurl.FromString(purl)
+	if err != nil {
+		return Upstream{}
+	}
+	v := u.Qualifiers.Map()["upstream"]
+	if v == "" {
+		return Upstream{}
+	}
+	if u.Type == "rpm" {
+		return parseSourceRPM(v)
+	}
+	name, version, _ := strings.Cut(v, "@")
+	return Upstream{Name: name, Version: version}
+}
+
+// parseSourceRPM splits a source RPM file name such as
+// openssl-3.0.7-24.el9.src.rpm into name and version-release.
+func parseSourceRPM(s string) Upstream {
+	s = strings.TrimSuffix(strings.TrimSuffix(s, ".rpm"), ".src")
+	release := strings.LastIndexByte(s, '-')
+	if release <= 0 {
+		return Upstream{Name: s}
+	}
+	version := strings.LastIndexByte(s[:release], '-')
+	if version <= 0 {
+		return Upstream{Name: s}
+	}
+	return Upstream{Name: s[:version], Version: s[version+1:]}
+}
@@ -3,6 +3,8 @@ package sbom
 // NormalizePackage is stable, 'policy-friendly' view of a package
 // Keep this small and consistent; it becomes contract for policy + vulns scan
 type NormalizedPackage struct {
+	// ID is stable across runs; relationships refer to packages by it.
+	ID        string   `json:"id"`
 	Name      string   `json:"name"`
 	Version   string   `json:"version"`
 	Type      string   `json:"type,omitempty"` // ecosystem (npm, apk, deb, rpm, go-module, ...)
@@ -10,4 +12,11 @@ type NormalizedPackage struct {
 	Licences  []string `json:"licences,omitempty"`
 	Locations []string `json:"locations,omitempty"`
 	FoundBy   string   `json:"found_by,omitempty"` // cataloger name if present
+
+	CPEs       []string  `json:"cpes,omitempty"`
+	Digests    []Digest  `json:"digests,omitempty"`    // of the files the package was found by
+	Supplier   string    `json:"supplier,omitempty"`   // who distributes the package
+	Originator string    `json:"originator,omitempty"` // who wrote it
+	Language   string    `json:"language,omitempty"`   // e.g. go, python; empty for OS packages
+	Upstream   *Upstream `json:"upstream,omitempty"`   // distro source package, when it differs
 }
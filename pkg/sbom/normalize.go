@@ -3,8 +3,10 @@ package sbom
 import (
 	"strings"
 
+	"github.com/anchore/syft/syft/file"
 	"github.com/anchore/syft/syft/pkg"
 	syftsobm "github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
 )
 
 // Software Package Data Exchange (SPDX) is an open standard (or format) for communicating Software Bill of Materials (SBOM) information including components, licenses, copyrights, and security references.
@@ -26,17 +28,46 @@ func NormalizePackage(doc *syftsobm.SBOM) []NormalizedPackage {
 
 	out := make([]NormalizedPackage, 0, len(pkgs))
 	for _, p := range pkgs {
-		out = append(out, normalizeOne(p))
+		out = append(out, normalizeOne(p, doc.Artifacts.FileDigests))
 	}
 
 	return out
 }
 
-func normalizeOne(p pkg.Package) NormalizedPackage {
+// DistroFromSBOM returns the Linux distribution Syft detected, if any.
+func DistroFromSBOM(doc *syftsobm.SBOM) *Distro {
+	if doc == nil || doc.Artifacts.LinuxDistribution == nil {
+		return nil
+	}
+	r := doc.Artifacts.LinuxDistribution
+	if r.ID == "" {
+		return nil
+	}
+	return &Distro{ID: r.ID, VersionID: r.VersionID}
+}
+
+// ImageDigestFromSBOM returns the manifest digest of the image the SBOM was
+// cataloged from, or "" for other sources.
+func ImageDigestFromSBOM(doc *syftsobm.SBOM) string {
+	if doc == nil {
+		return ""
+	}
+	switch m := doc.Source.Metadata.(type) {
+	case source.ImageMetadata:
+		return m.ManifestDigest
+	case *source.ImageMetadata:
+		return m.ManifestDigest
+	}
+	return ""
+}
+
+// normalizeOne converts one package; digests are the SBOM's file digests,
+// of which the package's evidence files are kept.
+func normalizeOne(p pkg.Package, digests map[file.Coordinates][]file.Digest) NormalizedPackage {
 	licenses := normalizeLicenses(p.Licenses)
 	locations := normalizeLocations(p.Locations)
 
-	return NormalizedPackage{
+	n := NormalizedPackage{
 		Name:      p.Name,
 		Version:   p.Version,
 		Type:      p.Type.String(),
@@ -44,7 +75,14 @@ func normalizeOne(p pkg.Package) NormalizedPackage {
 		Locations: locations,
 		PURL:      strings.TrimSpace(p.PURL),
 		FoundBy:   p.FoundBy,
+		CPEs:      normalizeCPEs(p),
+		Digests:   evidenceDigests(p, digests),
+		Language:  string(p.Language),
+		Upstream:  upstreamOf(p),
 	}
+	n.Supplier, n.Originator = supplierOriginator(p)
+	n.ID = stablePackageID(n)
+	return n
 }
 
 func normalizeLicenses(ls pkg.LicenseSet) []string {
@@ -3,6 +3,7 @@ package sbom
 import (
 	"testing"
 
+	"github.com/anchore/syft/syft/cpe"
 	"github.com/anchore/syft/syft/file"
 	"github.com/anchore/syft/syft/license"
 	"github.com/anchore/syft/syft/pkg"
@@ -46,3 +47,104 @@ func TestNormalizePackages_Basic(t *testing.T) {
 		t.Fatalf("expected at least one location")
 	}
 }
+
+func TestNormalizePackages_Metadata(t *testing.T) {
+	loc := file.NewLocation("/var/lib/dpkg/status").WithAnnotation(pkg.EvidenceAnnotationKey, pkg.PrimaryEvidenceAnnotation)
+	p := pkg.Package{
+		Name:      "libssl3",
+		Version:   "3.0.2-0ubuntu1.10",
+		Type:      pkg.DebPkg,
+		PURL:      "pkg:deb/ubuntu/libssl3@3.0.2-0ubuntu1.10?arch=amd64",
+		Language:  pkg.UnknownLanguage,
+		Locations: file.NewLocationSet(loc),
+		CPEs:      []cpe.CPE{cpe.Must("cpe:2.3:a:libssl3:libssl3:3.0.2-0ubuntu1.10:*:*:*:*:*:*:*", cpe.GeneratedSource)},
+		Metadata: pkg.DpkgDBEntry{
+			Package:       "libssl3",
+			Source:        "openssl",
+			SourceVersion: "3.0.2-0ubuntu1.10",
+			Maintainer:    "Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>",
+		},
+	}
+	doc := &syftsbom.SBOM{
+		Artifacts: syftsbom.Artifacts{
+			Packages: pkg.NewCollection(p),
+			FileDigests: map[file.Coordinates][]file.Digest{
+				loc.Coordinates: {{Algorithm: "SHA256", Value: "abc123"}},
+			},
+		},
+	}
+
+	n := NormalizePackage(doc)[0]
+	if n.Supplier != "Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>" || n.Originator != n.Supplier {
+		t.Errorf("supplier/originator = %q/%q", n.Supplier, n.Originator)
+	}
+	if n.Upstream == nil || *n.Upstream != (Upstream{Name: "openssl", Version: "3.0.2-0ubuntu1.10"}) {
+		t.Errorf("upstream = %+v", n.Upstream)
+	}
+	if len(n.CPEs) != 1 || n.CPEs[0] != "cpe:2.3:a:libssl3:libssl3:3.0.2-0ubuntu1.10:*:*:*:*:*:*:*" {
+		t.Errorf("cpes = %v", n.CPEs)
+	}
+	want := Digest{Path: "/var/lib/dpkg/status", Algorithm: "sha256", Value: "abc123"}
+	if len(n.Digests) != 1 || n.Digests[0] != want {
+		t.Errorf("digests = %+v", n.Digests)
+	}
+}
+
+func TestUpstream(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Upstream
+	}{
+		{"openssl-3.0.7-24.el9.src.rpm", Upstream{Name: "openssl", Version: "3.0.7-24.el9"}},
+		{"python3-pip-21.3.1-1.fc36.src.rpm", Upstream{Name: "python3-pip", Version: "21.3.1-1.fc36"}},
+		{"broken", Upstream{Name: "broken"}},
+	}
+	for _, c := range cases {
+		if got := parseSourceRPM(c.in); got != c.want {
+			t.Errorf("parseSourceRPM(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+
+	got := upstreamFromPURL("pkg:deb/debian/libssl3@3.0.11-1?arch=amd64&upstream=openssl%403.0.11")
+	if got != (Upstream{Name: "openssl", Version: "3.0.11"}) {
+		t.Errorf("upstreamFromPURL = %+v", got)
+	}
+}
+
+func TestLoadFile_DocumentMetadata(t *testing.T) {
+	const sha = "5a1f6c2b7e3d4c9a8b0e1f2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6e5f4a3"
+	cases := []struct {
+		file, supplier, originator string
+	}{
+		{"testdata/spdx.json", "Debian", "Debian OpenSSL Team (pkg-openssl-devel@lists.alioth.debian.org)"},
+		{"testdata/spdx.spdx", "Debian", "Debian OpenSSL Team (pkg-openssl-devel@lists.alioth.debian.org)"},
+		{"testdata/cyclonedx.json", "Debian", "Debian OpenSSL Team"},
+		{"testdata/cyclonedx.xml", "Debian", "Debian OpenSSL Team"},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			s, err := LoadFile(c.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var p *NormalizedPackage
+			for i := range s.Packages {
+				if s.Packages[i].Name == "openssl" {
+					p = &s.Packages[i]
+				}
+			}
+			if p == nil {
+				t.Fatalf("openssl not found in %+v", s.Packages)
+			}
+			if p.Supplier != c.supplier || p.Originator != c.originator {
+				t.Errorf("supplier/originator = %q/%q", p.Supplier, p.Originator)
+			}
+			if len(p.Digests) != 1 || p.Digests[0] != (Digest{Algorithm: "sha256", Value: sha}) {
+				t.Errorf("digests = %+v", p.Digests)
+			}
+			if len(p.CPEs) != 1 || p.CPEs[0] != "cpe:2.3:a:openssl:openssl:3.0.2:*:*:*:*:*:*:*" {
+				t.Errorf("cpes = %v", p.CPEs)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package sbom
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Quality fields. The first seven are the NTIA minimum elements; the rest
+// are what vulnerability matching and license policy additionally need.
+const (
+	FieldSupplier      = "supplier"
+	FieldName          = "name"
+	FieldVersion       = "version"
+	FieldIdentifier    = "identifier" // PURL or CPE
+	FieldRelationships = "relationships"
+	FieldAuthor        = "author"
+	FieldTimestamp     = "timestamp"
+	FieldLicense       = "license"
+	FieldHash          = "hash"
+)
+
+// FieldCoverage is how many packages carry a field. Document-level fields
+// (author, timestamp) have a total of 1.
+type FieldCoverage struct {
+	Field    string  `json:"field"`
+	NTIA     bool    `json:"ntia"`
+	Covered  int     `json:"covered"`
+	Total    int     `json:"total"`
+	Coverage float64 `json:"coverage"` // percent
+}
+
+// Quality scores an SBOM on the NTIA minimum elements and on license and
+// hash coverage.
+type Quality struct {
+	Format   string          `json:"format"`
+	Packages int             `json:"packages"`
+	Authors  []string        `json:"authors,omitempty"`
+	Created  *time.Time      `json:"created,omitempty"`
+	Fields   []FieldCoverage `json:"fields"`
+
+	// Score is the mean coverage of all fields and NTIAScore that of the
+	// NTIA ones, both 0-100. NTIA holds when every NTIA field is covered
+	// for every package.
+	Score     float64 `json:"score"`
+	NTIAScore float64 `json:"ntiaScore"`
+	NTIA      bool    `json:"ntiaCompliant"`
+}
+
+// Field returns the coverage of one field.
+func (q Quality) Field(name string) (FieldCoverage, bool) {
+	for _, f := range q.Fields {
+		if f.Field == name {
+			return f, true
+		}
+	}
+	return FieldCoverage{}, false
+}
+
+// Summary is a one-line description for reports.
+func (q Quality) Summary() string {
+	ntia := "not NTIA minimum elements compliant"
+	if q.NTIA {
+		ntia = "NTIA minimum elements compliant"
+	}
+	return fmt.Sprintf("quality score %.0f/100 over %d packages (NTIA %.0f/100, %s)", q.Score, q.Packages, q.NTIAScore, ntia)
+}
+
+// Missing lists the fields with less than full coverage.
+func (q Quality) Missing() []FieldCoverage {
+	var out []FieldCoverage
+	for _, f := range q.Fields {
+		if f.Covered < f.Total || f.Total == 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ScoreQuality measures field coverage of s. An SBOM without packages
+// scores zero on every package field.
+func ScoreQuality(s *ResolvedSBOM) Quality {
+	info := documentInfo(s.Format, s.RawPayload)
+	q := Quality{
+		Format:   s.Format,
+		Packages: len(s.Packages),
+		Authors:  info.Authors,
+	}
+	if !info.Created.IsZero() {
+		q.Created = &info.Created
+	}
+
+	related := map[string]bool{}
+	for _, r := range s.Relationships {
+		if r.Type == DependsOn || r.Type == Contains {
+			related[r.From] = true
+			related[r.To] = true
+		}
+	}
+
+	count := func(has func(NormalizedPackage) bool) int {
+		n := 0
+		for _, p := range s.Packages {
+			if has(p) {
+				n++
+			}
+		}
+		return n
+	}
+	perPackage := func(field string, ntia bool, has func(NormalizedPackage) bool) FieldCoverage {
+		return coverage(field, ntia, count(has), len(s.Packages))
+	}
+	document := func(field string, ok bool) FieldCoverage {
+		if ok {
+			return coverage(field, true, 1, 1)
+		}
+		return coverage(field, true, 0, 1)
+	}
+
+	q.Fields = []FieldCoverage{
+		perPackage(FieldSupplier, true, func(p NormalizedPackage) bool { return p.Supplier != "" }),
+		perPackage(FieldName, true, func(p NormalizedPackage) bool { return p.Name != "" }),
+		perPackage(FieldVersion, true, func(p NormalizedPackage) bool { return p.Version != "" }),
+		perPackage(FieldIdentifier, true, func(p NormalizedPackage) bool { return p.PURL != "" || len(p.CPEs) > 0 }),
+		perPackage(FieldRelationships, true, func(p NormalizedPackage) bool { return related[p.ID] }),
+		document(FieldAuthor, len(info.Authors) > 0),
+		document(FieldTimestamp, !info.Created.IsZero()),
+		perPackage(FieldLicense, false, hasLicense),
+		perPackage(FieldHash, false, func(p NormalizedPackage) bool { return len(p.Digests) > 0 }),
+	}
+
+	var all, ntia float64
+	var nNTIA int
+	q.NTIA = true
+	for _, f := range q.Fields {
+		all += f.Coverage
+		if f.NTIA {
+			ntia += f.Coverage
+			nNTIA++
+			if f.Total == 0 || f.Covered < f.Total {
+				q.NTIA = false
+			}
+		}
+	}
+	q.Score = all / float64(len(q.Fields))
+	q.NTIAScore = ntia / float64(nNTIA)
+	return q
+}
+
+func coverage(field string, ntia bool, covered, total int) FieldCoverage {
+	f := FieldCoverage{Field: field, NTIA: ntia, Covered: covered, Total: total}
+	if total > 0 {
+		f.Coverage = 100 * float64(covered) / float64(total)
+	}
+	return f
+}
+
+// hasLicense ignores NOASSERTION, which SPDX producers write when they did
+// not look.
+func hasLicense(p NormalizedPackage) bool {
+	for _, l := range p.Licences {
+		if !strings.EqualFold(l, "NOASSERTION") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package sbom
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScoreQuality(t *testing.T) {
+	raw := []byte(`{"bomFormat":"CycloneDX","specVersion":"1.5","metadata":{
+		"timestamp":"2024-05-01T10:00:00Z",
+		"tools":{"components":[{"name":"syft"}]}}}`)
+	s := &ResolvedSBOM{
+		Format:     "cyclonedx-json",
+		RawPayload: raw,
+		Packages: []NormalizedPackage{
+			{ID: "a", Name: "app", Version: "1.0", PURL: "pkg:npm/app@1.0", Supplier: "Acme", Licences: []string{"MIT"}},
+			{ID: "b", Name: "lib", Version: "2.0", CPEs: []string{"cpe:2.3:a:x:lib:2.0:*:*:*:*:*:*:*"}, Supplier: "X",
+				Licences: []string{"NOASSERTION"}, Digests: []Digest{{Algorithm: "sha256", Value: "ff"}}},
+		},
+		Relationships: []Relationship{{From: "a", To: "b", Type: DependsOn}},
+	}
+
+	q := ScoreQuality(s)
+	if !q.NTIA {
+		t.Errorf("expected NTIA compliance, missing %+v", q.Missing())
+	}
+	if q.NTIAScore != 100 {
+		t.Errorf("NTIA score = %v, want 100", q.NTIAScore)
+	}
+	if len(q.Authors) != 1 || q.Authors[0] != "syft" {
+		t.Errorf("authors = %v", q.Authors)
+	}
+	if q.Created == nil || !q.Created.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("created = %v", q.Created)
+	}
+	for _, field := range []string{FieldLicense, FieldHash} {
+		if f, _ := q.Field(field); f.Covered != 1 || f.Coverage != 50 {
+			t.Errorf("%s coverage = %+v, want 1 of 2", field, f)
+		}
+	}
+	// seven fields at 100%, two at 50%
+	if want := (7*100 + 2*50) / 9.0; q.Score != want {
+		t.Errorf("score = %v, want %v", q.Score, want)
+	}
+}
+
+func TestScoreQuality_Files(t *testing.T) {
+	spdx, err := LoadFile("testdata/spdx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := ScoreQuality(spdx)
+	if f, _ := q.Field(FieldAuthor); f.Covered != 1 {
+		t.Errorf("spdx author not found: %+v", q.Authors)
+	}
+	if f, _ := q.Field(FieldTimestamp); f.Covered != 1 {
+		t.Errorf("spdx timestamp not found")
+	}
+	if f, _ := q.Field(FieldSupplier); f.Coverage != 100 {
+		t.Errorf("spdx supplier coverage = %+v", f)
+	}
+
+	cdx, err := LoadFile("testdata/cyclonedx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q = ScoreQuality(cdx)
+	if q.NTIA {
+		t.Error("CycloneDX without metadata should not be NTIA compliant")
+	}
+	missing := map[string]bool{}
+	for _, f := range q.Missing() {
+		missing[f.Field] = true
+	}
+	if !missing[FieldAuthor] || !missing[FieldTimestamp] || missing[FieldVersion] {
+		t.Errorf("missing = %v", missing)
+	}
+}
+
+func TestScoreQuality_Empty(t *testing.T) {
+	q := ScoreQuality(&ResolvedSBOM{Format: "spdx-json"})
+	if q.NTIA || q.Score != 0 {
+		t.Errorf("empty SBOM scored %v (NTIA %v)", q.Score, q.NTIA)
+	}
+}
@@ -12,7 +12,7 @@ type ResolveOptions struct {
 func ResolveForImage(ctx context.Context, imageRef string) (*ResolvedSBOM, error) {
 
 	// Fallsback: generate SBOM on demand
-	gen, err := generateSBOMForImage(ctx, imageRef)
+	gen, err := generateSBOMForImageCached(ctx, imageRef)
 
 	if err != nil {
 		return nil, err
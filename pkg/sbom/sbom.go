@@ -5,16 +5,39 @@ import (
 	"fmt"
 )
 
+// ExtractOptions tune ExtractSBOMWithOptions.
+type ExtractOptions struct {
+	// NoCache always runs Syft instead of reusing a cached SBOM.
+	NoCache bool
+}
+
 func ExtractSBOM(ctx context.Context, image string) (*ResolvedSBOM, error) {
-	fmt.Println("[sbom] Extracting SBOM for:", image)
+	return ExtractSBOMWithOptions(ctx, image, ExtractOptions{})
+}
 
-	genSbom, err := generateSBOMForImage(ctx, image)
+// ExtractSBOMWithOptions catalogs an image reference or a local source
+// (see SplitSource). sbom: sources are decoded as they are; the other local
+// sources are cataloged without caching, since they have no registry digest
+// to key the cache on.
+func ExtractSBOMWithOptions(ctx context.Context, image string, opts ExtractOptions) (*ResolvedSBOM, error) {
+	var generate func(context.Context, string) (*ResolvedSBOM, error)
+	switch scheme, path, ok := SplitSource(image); {
+	case ok && scheme == SchemeSBOM:
+		return LoadFile(path)
+	case ok:
+		generate = func(ctx context.Context, _ string) (*ResolvedSBOM, error) {
+			return generateSBOM(ctx, path, syftSources[scheme])
+		}
+	case opts.NoCache:
+		generate = generateSBOMForImage
+	default:
+		generate = generateSBOMForImageCached
+	}
+	genSbom, err := generate(ctx, image)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate SBOM: %w", err)
 	}
 
-	fmt.Printf("Generated SBOM for image %s: %+v\n", image, genSbom)
-
 	return genSbom, nil
 }
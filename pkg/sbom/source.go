@@ -1,15 +1,74 @@
 package sbom
 
+import (
+	"strings"
+
+	syftsobm "github.com/anchore/syft/syft/sbom"
+)
+
 type SourceType string
 
 const (
 	SourceAttestation SourceType = "attestation"
 	SourceGenerated   SourceType = "generated"
+	SourceFile        SourceType = "file"
+)
+
+// Prefixes for sources on disk. Any other input is an image reference.
+const (
+	SchemeOCILayout     = "oci-layout"     // OCI image layout directory
+	SchemeDockerArchive = "docker-archive" // tarball from docker save
+	SchemeDir           = "dir"            // unpacked filesystem
+	SchemeSBOM          = "sbom"           // existing SBOM document, not cataloged again
 )
 
+// syftSources maps local schemes to the Syft source provider that reads them.
+var syftSources = map[string]string{
+	SchemeOCILayout:     "oci-dir",
+	SchemeDockerArchive: "docker-archive",
+	SchemeDir:           "dir",
+}
+
+// SplitSource separates a local source such as "dir:./rootfs" into its
+// scheme and path. ok is false for image references, including ones with a
+// registry port like localhost:5000/app.
+func SplitSource(input string) (scheme, path string, ok bool) {
+	scheme, path, found := strings.Cut(input, ":")
+	if !found || path == "" {
+		return "", "", false
+	}
+	if _, known := syftSources[scheme]; !known && scheme != SchemeSBOM {
+		return "", "", false
+	}
+	return scheme, path, true
+}
+
+// IsLocalSource reports whether input names a source on disk, which has no
+// registry to verify attestations or fetch metadata from.
+func IsLocalSource(input string) bool {
+	_, _, ok := SplitSource(input)
+	return ok
+}
+
 type ResolvedSBOM struct {
-	Source     SourceType
-	Format     string
-	Packages   []NormalizedPackage
-	RawPayload []byte // optional; useful for debugging or export
+	Source   SourceType
+	Format   string
+	Packages []NormalizedPackage
+	// Relationships between Packages (and the files they were found by).
+	Relationships []Relationship
+	Distro        *Distro // nil when the SBOM does not record the OS
+	// ImageDigest is the manifest digest of the cataloged image, when the
+	// SBOM records one; local sources have no registry to ask.
+	ImageDigest string
+	RawPayload  []byte // optional; useful for debugging or export
+
+	doc *syftsobm.SBOM // set when generated or loaded in this process
+}
+
+// Distro identifies the Linux distribution packages were cataloged from.
+// OS package advisories are per release, so scanners need it to pick the
+// right ecosystem (Debian:12, Alpine:v3.19).
+type Distro struct {
+	ID        string `json:"id"`        // os-release ID, e.g. "debian", "alpine"
+	VersionID string `json:"versionID"` // os-release VERSION_ID, e.g. "12", "3.19.1"
 }
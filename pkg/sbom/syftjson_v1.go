@@ -0,0 +1,7 @@
+//go:build !goexperiment.jsonv2
+
+package sbom
+
+// syftJSONDecodable is false when Syft's syft-json decoder cannot run; see
+// syftjson_v2.go.
+const syftJSONDecodable = true
@@ -0,0 +1,9 @@
+//go:build goexperiment.jsonv2
+
+package sbom
+
+// Syft's syft-json Document.UnmarshalJSON unmarshals through a named
+// pointer alias of itself. encoding/json on the v2 implementation calls the
+// method again for that alias and recurses until the stack overflows, so
+// decoding is refused up front in these builds.
+const syftJSONDecodable = false
@@ -0,0 +1,165 @@
+package version
+
+import (
+	"strings"
+)
+
+// compareAPK is a port of apk-tools' version comparison. An Alpine version
+// is digits{.digits}[letter]{_suffix[N]}[-rN]; alpha/beta/pre/rc suffixes
+// sort before the release and cvs/svn/git/hg/p after it.
+func compareAPK(a, b string) int {
+	at := &apkTokenizer{s: strings.TrimSpace(a), typ: apkDigit}
+	bt := &apkTokenizer{s: strings.TrimSpace(b), typ: apkDigit}
+
+	av, bv := 0, 0
+	for at.typ == bt.typ && at.typ != apkEnd && at.typ != apkInvalid && av == bv {
+		av = at.next()
+		bv = bt.next()
+	}
+
+	if av != bv {
+		return sign(av - bv)
+	}
+	if at.typ == bt.typ {
+		return 0
+	}
+
+	// equal so far: the longer version is newer unless what follows is a
+	// pre-release suffix
+	if at.typ == apkSuffix && at.next() < 0 {
+		return -1
+	}
+	if bt.typ == apkSuffix && bt.next() < 0 {
+		return 1
+	}
+	return sign(int(bt.typ) - int(at.typ))
+}
+
+type apkToken int
+
+const (
+	apkInvalid apkToken = iota - 1
+	apkDigitOrZero
+	apkDigit
+	apkLetter
+	apkSuffix
+	apkSuffixNo
+	apkRevisionNo
+	apkEnd
+)
+
+var (
+	apkPreSuffixes  = []string{"alpha", "beta", "pre", "rc"}
+	apkPostSuffixes = []string{"cvs", "svn", "git", "hg", "p"}
+)
+
+type apkTokenizer struct {
+	s   string
+	typ apkToken
+}
+
+// next returns the value of the current token and advances typ to the
+// type of the following one.
+func (t *apkTokenizer) next() int {
+	if len(t.s) == 0 {
+		t.typ = apkEnd
+		return 0
+	}
+
+	v, i := 0, 0
+	nt := apkInvalid
+
+	switch t.typ {
+	case apkDigitOrZero, apkDigit, apkSuffixNo, apkRevisionNo:
+		if t.typ == apkDigitOrZero && t.s[0] == '0' {
+			// leading zeros compare as a fraction: 1.01 < 1.1
+			for i < len(t.s) && t.s[i] == '0' {
+				i++
+			}
+			if i < len(t.s) && isDigit(t.s[i]) {
+				nt = apkDigit
+				v = -i
+				break
+			}
+			i = 0 // a plain zero component
+		}
+		for i < len(t.s) && isDigit(t.s[i]) {
+			v = v*10 + int(t.s[i]-'0')
+			i++
+		}
+	case apkLetter:
+		v = int(t.s[0])
+		i = 1
+	case apkSuffix:
+		found := false
+		for n, suf := range apkPreSuffixes {
+			if strings.HasPrefix(t.s, suf) {
+				v, i, found = n-len(apkPreSuffixes), len(suf), true
+				break
+			}
+		}
+		if !found {
+			for n, suf := range apkPostSuffixes {
+				if strings.HasPrefix(t.s, suf) {
+					v, i, found = n, len(suf), true
+					break
+				}
+			}
+		}
+		if !found {
+			t.typ = apkInvalid
+			return -1
+		}
+	default:
+		t.typ = apkInvalid
+		return -1
+	}
+
+	t.s = t.s[i:]
+	switch {
+	case len(t.s) == 0:
+		t.typ = apkEnd
+	case nt != apkInvalid:
+		t.typ = nt
+	default:
+		t.advance()
+	}
+	return v
+}
+
+// advance consumes the separator after a token and works out the next type.
+func (t *apkTokenizer) advance() {
+	n := apkInvalid
+	c := t.s[0]
+	switch {
+	case (t.typ == apkDigit || t.typ == apkDigitOrZero) && c >= 'a' && c <= 'z':
+		n = apkLetter
+	case t.typ == apkLetter && isDigit(c):
+		n = apkDigit
+	case t.typ == apkSuffix && isDigit(c):
+		n = apkSuffixNo
+	default:
+		switch c {
+		case '.':
+			n = apkDigitOrZero
+		case '_':
+			n = apkSuffix
+		case '-':
+			if len(t.s) > 1 && t.s[1] == 'r' {
+				n = apkRevisionNo
+				t.s = t.s[1:]
+			}
+		}
+		t.s = t.s[1:]
+	}
+
+	// tokens only move forward, apart from these repeatable transitions
+	if n < t.typ {
+		if !((n == apkDigitOrZero && t.typ == apkDigit) ||
+			(n == apkSuffix && t.typ == apkSuffixNo) ||
+			(n == apkDigit && t.typ == apkLetter)) {
+			n = apkInvalid
+		}
+	}
+	t.typ = n
+}
@@ -0,0 +1,23 @@
+package version
+
+import "testing"
+
+func TestAPK(t *testing.T) {
+	assertOrdered(t, APK, []string{
+		"1.0_alpha1", "1.0_alpha2", "1.0_beta1", "1.0_pre1", "1.0_rc1", "1.0_rc2", "1.0", "1.0-r1", "1.0-r2", "1.0_p1",
+		"1.0a", "1.0b", "1.0.1", "1.1",
+	})
+	assertOrdered(t, APK, []string{
+		"1.36.1-r5", "1.36.1-r15", "1.36.1-r19", "1.37.0-r0",
+	})
+	assertOrdered(t, APK, []string{
+		"3.1.4-r0", "3.1.4-r5", "3.1.4_git20240101-r0", "3.2.0_rc1-r0", "3.2.0-r0",
+	})
+	// leading zeros compare as fractions
+	assertOrdered(t, APK, []string{"1.001", "1.01", "1.1", "1.2"})
+
+	assertEqual(t, APK, [][2]string{
+		{"1.2.3", "1.2.3"},
+		{"1.2.3-r0", "1.2.3-r0"},
+	})
+}
@@ -0,0 +1,93 @@
+package version
+
+import (
+	"strings"
+)
+
+// compareDebian implements dpkg's version ordering: epoch, then upstream
+// version, then Debian revision, each compared with verrevcmp where '~'
+// sorts before everything (even the end of the string).
+// Spec: https://www.debian.org/doc/debian-policy/ch-controlfields.html#version
+func compareDebian(a, b string) int {
+	ae, au, ar := splitDebian(a)
+	be, bu, br := splitDebian(b)
+
+	if c := compareDigits(ae, be); c != 0 {
+		return c
+	}
+	if c := verrevcmp(au, bu); c != 0 {
+		return c
+	}
+	return verrevcmp(ar, br)
+}
+
+func splitDebian(v string) (epoch, upstream, revision string) {
+	v = strings.TrimSpace(v)
+	epoch = "0"
+	if e, rest, ok := strings.Cut(v, ":"); ok && isDigits(e) {
+		epoch, v = e, rest
+	}
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		return epoch, v[:i], v[i+1:]
+	}
+	return epoch, v, ""
+}
+
+// debOrder is dpkg's character weight: '~' first, then the end of the
+// string (and digits, which are handled separately), then letters, then
+// everything else.
+func debOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+func verrevcmp(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := debOrder(a, i), debOrder(b, j)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
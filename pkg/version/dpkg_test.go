@@ -0,0 +1,23 @@
+package version
+
+import "testing"
+
+func TestDebian(t *testing.T) {
+	assertOrdered(t, Debian, []string{
+		"0.9", "1.0~~", "1.0~~a", "1.0~", "1.0", "1.0-1", "1.0-1+b1", "1.0a", "1.0+dfsg", "1.0.1", "1.1", "1:0.1",
+	})
+	assertOrdered(t, Debian, []string{
+		"2.36-9", "2.36-9+deb12u1", "2.36-9+deb12u4", "2.36-10", "2.37-1~bpo12+1", "2.37-1",
+	})
+	assertOrdered(t, Debian, []string{
+		"3.0.11-1~deb12u1", "3.0.11-1~deb12u2", "3.0.13-1~deb12u1", "3.0.14-1~deb12u1",
+	})
+	assertOrdered(t, Debian, []string{"1.2.3~rc1-1", "1.2.3-1", "1.2.3-1ubuntu0.1", "1.2.3-1ubuntu1", "1.2.3-1ubuntu1.1"})
+
+	assertEqual(t, Debian, [][2]string{
+		{"1.0", "0:1.0"},
+		{"1.0", "1.0-0"},
+		{"1.01", "1.1"},
+		{"1.0-01", "1.0-1"},
+	})
+}
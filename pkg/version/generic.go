@@ -0,0 +1,100 @@
+package version
+
+import (
+	"strings"
+	"unicode"
+)
+
+// compareGeneric splits versions into numeric and alphabetic runs; numbers
+// compare numerically, text lexically, and a number sorts after text
+// (1.0.1 > 1.0.rc1). Good enough for dotted versions of unknown schemes.
+func compareGeneric(a, b string) int {
+	as, bs := segments(a), segments(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		xd, yd := isDigits(x), isDigits(y)
+		switch {
+		case xd && yd:
+			if c := compareDigits(x, y); c != 0 {
+				return c
+			}
+		case xd:
+			return 1
+		case yd:
+			return -1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func segments(v string) []string {
+	var out []string
+	var cur []rune
+	digit := false
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = cur[:0]
+		}
+	}
+	for _, r := range strings.TrimPrefix(v, "v") {
+		switch {
+		case unicode.IsDigit(r):
+			if !digit {
+				flush()
+			}
+			digit = true
+			cur = append(cur, r)
+		case unicode.IsLetter(r):
+			if digit {
+				flush()
+			}
+			digit = false
+			cur = append(cur, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out
+}
+
+// compareDigits compares two decimal strings of any length.
+func compareDigits(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isAlpha(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
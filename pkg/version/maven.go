@@ -0,0 +1,218 @@
+package version
+
+import (
+	"strings"
+)
+
+// compareMaven implements Maven's ComparableVersion: versions are split on
+// '.', '-' and digit/letter transitions into nested lists, well-known
+// qualifiers are ranked (alpha < beta < milestone < rc < snapshot < release
+// < sp) and other qualifiers sort after them alphabetically.
+// Spec: https://maven.apache.org/pom.html#version-order-specification
+func compareMaven(a, b string) int {
+	return parseMaven(a).compare(parseMaven(b))
+}
+
+type mavenKind int
+
+const (
+	mavenInt mavenKind = iota
+	mavenString
+	mavenList
+)
+
+type mavenItem struct {
+	kind  mavenKind
+	value string // digits without leading zeros for ints, qualifier for strings
+	items []*mavenItem
+}
+
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// mavenReleaseIndex is the comparable form of the empty (release) qualifier.
+const mavenReleaseIndex = "5"
+
+func parseMaven(v string) *mavenItem {
+	v = strings.ToLower(strings.TrimSpace(v))
+
+	root := &mavenItem{kind: mavenList}
+	list := root
+	stack := []*mavenItem{root}
+	push := func() {
+		sub := &mavenItem{kind: mavenList}
+		list.items = append(list.items, sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+
+	digit := false
+	start := 0
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '.':
+			if i == start {
+				list.items = append(list.items, mavenNumber("0"))
+			} else {
+				list.items = append(list.items, mavenParseItem(digit, v[start:i]))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.items = append(list.items, mavenNumber("0"))
+			} else {
+				list.items = append(list.items, mavenParseItem(digit, v[start:i]))
+			}
+			start = i + 1
+			push()
+		case isDigit(c):
+			if !digit && i > start {
+				// 1.0alpha1 => 1.0-alpha-1; a/b/m right before a number are short forms
+				list.items = append(list.items, mavenQualifier(v[start:i], true))
+				start = i
+				push()
+			}
+			digit = true
+		default:
+			if digit && i > start {
+				list.items = append(list.items, mavenParseItem(true, v[start:i]))
+				start = i
+				push()
+			}
+			digit = false
+		}
+	}
+	if len(v) > start {
+		list.items = append(list.items, mavenParseItem(digit, v[start:]))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return root
+}
+
+func mavenParseItem(digit bool, s string) *mavenItem {
+	if digit {
+		return mavenNumber(s)
+	}
+	return mavenQualifier(s, false)
+}
+
+func mavenNumber(s string) *mavenItem {
+	return &mavenItem{kind: mavenInt, value: strings.TrimLeft(s, "0")}
+}
+
+func mavenQualifier(s string, followedByDigit bool) *mavenItem {
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := mavenAliases[s]; ok {
+		s = alias
+	}
+	return &mavenItem{kind: mavenString, value: s}
+}
+
+// normalize drops trailing null items (0, release qualifiers, empty lists).
+func (l *mavenItem) normalize() {
+	for i := len(l.items) - 1; i >= 0; i-- {
+		it := l.items[i]
+		if it.isNull() {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+		} else if it.kind != mavenList {
+			break
+		}
+	}
+}
+
+func (it *mavenItem) isNull() bool {
+	switch it.kind {
+	case mavenInt:
+		return it.value == ""
+	case mavenString:
+		return it.value == ""
+	default:
+		return len(it.items) == 0
+	}
+}
+
+func comparableQualifier(q string) string {
+	for i, k := range mavenQualifiers {
+		if q == k {
+			return string(rune('0' + i))
+		}
+	}
+	return "7-" + q
+}
+
+// compare orders it against other; a nil other stands for padding.
+func (it *mavenItem) compare(other *mavenItem) int {
+	switch it.kind {
+	case mavenInt:
+		if other == nil {
+			if it.value == "" {
+				return 0
+			}
+			return 1
+		}
+		switch other.kind {
+		case mavenInt:
+			return compareDigits(it.value, other.value)
+		default:
+			return 1 // 1.1 > 1-sp and 1.1 > 1-1
+		}
+
+	case mavenString:
+		if other == nil {
+			return strings.Compare(comparableQualifier(it.value), mavenReleaseIndex)
+		}
+		switch other.kind {
+		case mavenString:
+			return strings.Compare(comparableQualifier(it.value), comparableQualifier(other.value))
+		default:
+			return -1 // 1.any < 1.1 and 1.any < 1-1
+		}
+
+	default:
+		if other == nil {
+			if len(it.items) == 0 {
+				return 0
+			}
+			return it.items[0].compare(nil)
+		}
+		switch other.kind {
+		case mavenInt:
+			return -1 // 1-1 < 1.0.x
+		case mavenString:
+			return 1 // 1-1 > 1-sp
+		}
+		for i := 0; i < len(it.items) || i < len(other.items); i++ {
+			var l, r *mavenItem
+			if i < len(it.items) {
+				l = it.items[i]
+			}
+			if i < len(other.items) {
+				r = other.items[i]
+			}
+			var c int
+			if l == nil {
+				c = -r.compare(nil)
+			} else {
+				c = l.compare(r)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
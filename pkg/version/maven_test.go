@@ -0,0 +1,45 @@
+package version
+
+import "testing"
+
+func TestMaven(t *testing.T) {
+	// lists from Maven's ComparableVersionTest
+	assertOrdered(t, Maven, []string{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+		"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+		"1-1", "1-2", "1-123",
+	})
+	assertOrdered(t, Maven, []string{
+		"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1",
+		"2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+	})
+
+	assertEqual(t, Maven, [][2]string{
+		{"1", "1.0"},
+		{"1", "1.0.0"},
+		{"1.0", "1.0.0"},
+		{"1", "1-0"},
+		{"1", "1.0-0"},
+		{"1.0", "1.0-0"},
+		{"1a", "1-a"},
+		{"1a", "1.0-a"},
+		{"1a", "1.0.0-a"},
+		{"1x", "1-x"},
+		{"1ga", "1"},
+		{"1release", "1"},
+		{"1final", "1"},
+		{"1cr", "1rc"},
+		{"1a1", "1-alpha-1"},
+		{"1b2", "1-beta-2"},
+		{"1m3", "1-milestone-3"},
+		{"1X", "1x"},
+		{"1A", "1a"},
+		{"1RC1", "1rc1"},
+		{"2.16.0.Final", "2.16.0"},
+		{"5.3.18.RELEASE", "5.3.18"},
+	})
+
+	// real-world shapes
+	assertOrdered(t, Maven, []string{"2.14.1", "2.15.0-rc1", "2.15.0", "2.17.0", "2.17.1"})
+	assertOrdered(t, Maven, []string{"1.0-SNAPSHOT", "1.0", "1.0-jre", "1.0.1-SNAPSHOT", "1.0.1"})
+}
@@ -0,0 +1,172 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pep440Pattern is the permissive pattern from the PyPA packaging library.
+// Spec: https://peps.python.org/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions
+var pep440Pattern = regexp.MustCompile(`^\s*v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?:[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?:-(?P<post_n1>[0-9]+)|[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?)?` +
+	`(?:[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?\s*$`)
+
+type pep440Version struct {
+	epoch   string
+	release []string
+	pre     string // "a", "b" or "rc"; empty when absent
+	preN    string
+	post    bool
+	postN   string
+	dev     bool
+	devN    string
+	local   []string
+}
+
+func parsePEP440(v string) (pep440Version, bool) {
+	m := pep440Pattern.FindStringSubmatch(strings.ToLower(v))
+	if m == nil {
+		return pep440Version{}, false
+	}
+	g := func(name string) string { return m[pep440Pattern.SubexpIndex(name)] }
+
+	p := pep440Version{
+		epoch:   orZero(g("epoch")),
+		release: strings.Split(g("release"), "."),
+	}
+	// trailing zeros are insignificant: 1.0 == 1.0.0
+	for len(p.release) > 1 && strings.Trim(p.release[len(p.release)-1], "0") == "" {
+		p.release = p.release[:len(p.release)-1]
+	}
+
+	switch g("pre_l") {
+	case "":
+	case "alpha", "a":
+		p.pre = "a"
+	case "beta", "b":
+		p.pre = "b"
+	default: // c, pre, preview, rc
+		p.pre = "rc"
+	}
+	p.preN = orZero(g("pre_n"))
+
+	if g("post_n1") != "" || g("post_l") != "" {
+		p.post = true
+		p.postN = orZero(g("post_n1") + g("post_n2"))
+	}
+	if g("dev_l") != "" {
+		p.dev = true
+		p.devN = orZero(g("dev_n"))
+	}
+	if l := g("local"); l != "" {
+		p.local = strings.FieldsFunc(l, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	}
+	return p, true
+}
+
+// comparePEP440 follows the ordering of packaging.version.Version:
+// epoch, release, then dev < pre < final < post, then local labels.
+func comparePEP440(a, b string) int {
+	x, xok := parsePEP440(a)
+	y, yok := parsePEP440(b)
+	if !xok || !yok {
+		return compareGeneric(a, b)
+	}
+
+	if c := compareDigits(x.epoch, y.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(x.release) || i < len(y.release); i++ {
+		if c := compareDigits(at(x.release, i), at(y.release, i)); c != 0 {
+			return c
+		}
+	}
+	if c := sign(x.preRank() - y.preRank()); c != 0 {
+		return c
+	}
+	if x.pre != "" {
+		if c := compareDigits(x.preN, y.preN); c != 0 {
+			return c
+		}
+	}
+	if c := compareOptional(x.post, x.postN, y.post, y.postN, -1); c != 0 {
+		return c
+	}
+	if c := compareOptional(x.dev, x.devN, y.dev, y.devN, 1); c != 0 {
+		return c
+	}
+	return compareLocal(x.local, y.local)
+}
+
+// preRank ranks the pre-release part. A bare dev release (1.0.dev1) sorts
+// before any pre-release of the same version, and no pre-release after all.
+func (p pep440Version) preRank() int {
+	switch p.pre {
+	case "a":
+		return 1
+	case "b":
+		return 2
+	case "rc":
+		return 3
+	}
+	if !p.post && p.dev {
+		return 0
+	}
+	return 4
+}
+
+// compareOptional orders an optional numeric component. absent is the
+// sign an absent component takes relative to a present one.
+func compareOptional(aHas bool, aN string, bHas bool, bN string, absent int) int {
+	switch {
+	case aHas && bHas:
+		return compareDigits(aN, bN)
+	case aHas:
+		return -absent
+	case bHas:
+		return absent
+	}
+	return 0
+}
+
+// compareLocal orders local version labels: no label sorts first, numeric
+// segments sort after alphanumeric ones, and a longer label wins a tie.
+func compareLocal(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, y := a[i], b[i]
+		xd, yd := isDigits(x), isDigits(y)
+		switch {
+		case xd && yd:
+			if c := compareDigits(x, y); c != 0 {
+				return c
+			}
+		case xd:
+			return 1
+		case yd:
+			return -1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(a) - len(b))
+}
+
+func at(s []string, i int) string {
+	if i < len(s) {
+		return s[i]
+	}
+	return "0"
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package version
+
+import "testing"
+
+func TestPEP440(t *testing.T) {
+	// ordering example from PEP 440 plus epochs and local labels
+	assertOrdered(t, PEP440, []string{
+		"1.0.dev456",
+		"1.0a1",
+		"1.0a2.dev456",
+		"1.0a12.dev456",
+		"1.0a12",
+		"1.0b1.dev456",
+		"1.0b2",
+		"1.0b2.post345.dev456",
+		"1.0b2.post345",
+		"1.0rc1.dev456",
+		"1.0rc1",
+		"1.0",
+		"1.0+abc.5",
+		"1.0+abc.7",
+		"1.0+5",
+		"1.0.post456.dev34",
+		"1.0.post456",
+		"1.0.15",
+		"1.1.dev1",
+		"2.0",
+		"1!0.1",
+	})
+
+	assertEqual(t, PEP440, [][2]string{
+		{"1.0", "1.0.0"},
+		{"v1.0", "1.0"},
+		{"1.0alpha1", "1.0a1"},
+		{"1.0-beta.2", "1.0b2"},
+		{"1.0c1", "1.0rc1"},
+		{"1.0pre1", "1.0rc1"},
+		{"1.0-1", "1.0.post1"},
+		{"1.0.rev1", "1.0.post1"},
+		{"1.0.post", "1.0.post0"},
+		{"1.0-dev", "1.0.dev0"},
+		{"0!1.0", "1.0"},
+		{"1.0RC1", "1.0rc1"},
+		{"1.0+ubuntu-1", "1.0+ubuntu.1"},
+	})
+
+	// Django-style releases
+	assertOrdered(t, PEP440, []string{"3.2.9", "3.2.10", "4.0a1", "4.0b1", "4.0rc1", "4.0", "4.0.1"})
+}
@@ -0,0 +1,140 @@
+package version
+
+import (
+	"strings"
+)
+
+// compareRPM orders [epoch:]version[-release] strings like rpm: epochs
+// numerically, then version and release with rpmvercmp. The release is only
+// compared when both sides have one, so "1.0" matches any "1.0-N".
+func compareRPM(a, b string) int {
+	ae, av, ar := splitRPM(a)
+	be, bv, br := splitRPM(b)
+
+	if c := compareDigits(ae, be); c != 0 {
+		return c
+	}
+	if c := rpmvercmp(av, bv); c != 0 {
+		return c
+	}
+	if ar == "" || br == "" {
+		return 0
+	}
+	return rpmvercmp(ar, br)
+}
+
+func splitRPM(v string) (epoch, version, release string) {
+	v = strings.TrimSpace(v)
+	epoch = "0"
+	if e, rest, ok := strings.Cut(v, ":"); ok && isDigits(e) {
+		epoch, v = e, rest
+	}
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		return epoch, v[:i], v[i+1:]
+	}
+	return epoch, v, ""
+}
+
+// rpmvercmp is a port of rpm's rpmvercmp: alternating numeric and alpha
+// segments, '~' sorts before anything and '^' after the base version but
+// before any further segment.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && !isAlnum(a[i]) && a[i] != '~' && a[i] != '^' {
+			i++
+		}
+		for j < len(b) && !isAlnum(b[j]) && b[j] != '~' && b[j] != '^' {
+			j++
+		}
+
+		ca, cb := charAt(a, i), charAt(b, j)
+		if ca == '~' || cb == '~' {
+			if ca != '~' {
+				return 1
+			}
+			if cb != '~' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		if ca == '^' || cb == '^' {
+			switch {
+			case i >= len(a):
+				return -1
+			case j >= len(b):
+				return 1
+			case ca != '^':
+				return 1
+			case cb != '^':
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+
+		si, sj := i, j
+		numeric := isDigit(a[i])
+		if numeric {
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+		} else {
+			for i < len(a) && isAlpha(a[i]) {
+				i++
+			}
+			for j < len(b) && isAlpha(b[j]) {
+				j++
+			}
+		}
+
+		if sj == j {
+			// segment types differ: numeric is newer than alpha
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		var c int
+		if numeric {
+			c = compareDigits(a[si:i], b[sj:j])
+		} else {
+			c = strings.Compare(a[si:i], b[sj:j])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case i >= len(a) && j >= len(b):
+		return 0
+	case i < len(a):
+		return 1
+	default:
+		return -1
+	}
+}
+
+func charAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return 0
+}
+
+func isAlnum(c byte) bool { return isDigit(c) || isAlpha(c) }
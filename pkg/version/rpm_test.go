@@ -0,0 +1,58 @@
+package version
+
+import "testing"
+
+func TestRPM(t *testing.T) {
+	assertOrdered(t, RPM, []string{
+		"1.0~rc1", "1.0", "1.0^git1", "1.0^git2", "1.0a", "1.0.1", "1.1", "1.10", "1:0.1", "2:0.1",
+	})
+	assertOrdered(t, RPM, []string{
+		"3.0.7-16.el9", "3.0.7-18.el9_2", "3.0.7-24.el9", "1:3.0.7-24.el9", "1:3.0.7-25.el9_3",
+	})
+	assertOrdered(t, RPM, []string{"2.28-225.el9", "2.28-225.el9_3.1", "2.28-236.el9"})
+
+	assertEqual(t, RPM, [][2]string{
+		{"1.0", "0:1.0"},
+		{"1.0", "1.0-5"}, // release only compared when both sides have one
+		{"1.01", "1.1"},
+		{"1.0.0", "1.0_0"},
+		{"1..0", "1.0"},
+	})
+
+	// straight from rpm's rpmvercmp test suite
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0.1", "2.0.1a", -1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz.4", "8", -1},
+		{"5.5p2", "5.6p1", -1},
+		{"6.0.rc1", "6.0", 1},
+		{"10a2", "10b2", -1},
+		{"1.0aa", "1.0a", 1},
+		{"10.0001", "10.1", 0},
+		{"10.0001", "10.0039", -1},
+		{"4.999.9", "5.0", -1},
+		{"20101121", "20101122", -1},
+		{"2_0", "2_0", 0},
+		{"2.0", "2_0", 0},
+		{"a", "a", 0},
+		{"a+", "a_", 0},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0^git1~pre", 1},
+		{"1.0^git1", "1.01", -1},
+	}
+	for _, tt := range tests {
+		if got := rpmvercmp(tt.a, tt.b); got != tt.want {
+			t.Errorf("rpmvercmp(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+var gemSegment = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+
+// compareRubyGems follows Gem::Version#<=>: numeric and alphabetic segments,
+// trailing zeros ignored, and any letter marks a pre-release
+// (1.0.a < 1.0 < 1.0.1). A "-" is read as ".pre." like RubyGems does.
+func compareRubyGems(a, b string) int {
+	as, bs := gemSegments(a), gemSegments(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x == y {
+			continue
+		}
+		xd, yd := isDigits(x), isDigits(y)
+		switch {
+		case xd && yd:
+			if c := compareDigits(x, y); c != 0 {
+				return c
+			}
+		case xd:
+			return 1
+		case yd:
+			return -1
+		default:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+// gemSegments returns Gem::Version#canonical_segments: trailing zeros are
+// dropped from the release part and from the pre-release part.
+func gemSegments(v string) []string {
+	v = strings.ReplaceAll(strings.TrimSpace(v), "-", ".pre.")
+	segs := gemSegment.FindAllString(v, -1)
+
+	split := len(segs)
+	for i, s := range segs {
+		if !isDigits(s) {
+			split = i
+			break
+		}
+	}
+	release := trimZeros(segs[:split])
+	pre := trimZeros(segs[split:])
+	return append(release, pre...)
+}
+
+func trimZeros(segs []string) []string {
+	n := len(segs)
+	for n > 0 && isDigits(segs[n-1]) && strings.Trim(segs[n-1], "0") == "" {
+		n--
+	}
+	return append([]string(nil), segs[:n]...)
+}
@@ -0,0 +1,19 @@
+package version
+
+import "testing"
+
+func TestRubyGems(t *testing.T) {
+	assertOrdered(t, RubyGems, []string{
+		"0.9", "1.0.a", "1.0.a.1", "1.0.a.2", "1.0.b", "1.0.rc1", "1.0", "1.0.1", "1.1", "1.9", "1.10", "2.0.0.pre", "2.0.0",
+	})
+	assertOrdered(t, RubyGems, []string{"7.0.8", "7.0.8.1", "7.1.0.beta1", "7.1.0.rc1", "7.1.0", "7.1.3.4"})
+
+	assertEqual(t, RubyGems, [][2]string{
+		{"1.0", "1"},
+		{"1.0.0", "1"},
+		{"1.0.a", "1.a"},
+		{"1.0.a.0", "1.a"},
+		{"1.0-1", "1.0.pre.1"},
+		{"1.0rc1", "1.0.rc.1"},
+	})
+}
@@ -0,0 +1,79 @@
+package version
+
+import (
+	"strings"
+)
+
+// compareSemver implements SemVer 2.0 precedence, leniently: a leading "v"
+// (Go) or "=" is ignored, missing minor/patch count as zero, and build
+// metadata is ignored. Cores that are not numeric fall back to Generic.
+// Spec: https://semver.org/#spec-item-11
+func compareSemver(a, b string) int {
+	ac, apre, aok := parseSemver(a)
+	bc, bpre, bok := parseSemver(b)
+	if !aok || !bok {
+		return compareGeneric(a, b)
+	}
+
+	for i := 0; i < len(ac) || i < len(bc); i++ {
+		x, y := "0", "0"
+		if i < len(ac) {
+			x = ac[i]
+		}
+		if i < len(bc) {
+			y = bc[i]
+		}
+		if c := compareDigits(x, y); c != 0 {
+			return c
+		}
+	}
+
+	// a version without pre-release has higher precedence
+	switch {
+	case apre == nil && bpre == nil:
+		return 0
+	case apre == nil:
+		return 1
+	case bpre == nil:
+		return -1
+	}
+
+	for i := 0; i < len(apre) && i < len(bpre); i++ {
+		x, y := apre[i], bpre[i]
+		xd, yd := isDigits(x), isDigits(y)
+		switch {
+		case xd && yd:
+			if c := compareDigits(x, y); c != 0 {
+				return c
+			}
+		case xd:
+			return -1 // numeric identifiers sort before alphanumeric ones
+		case yd:
+			return 1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(apre) - len(bpre))
+}
+
+func parseSemver(v string) (core, pre []string, ok bool) {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(v, "=")
+	v = strings.TrimPrefix(v, "v")
+	v, _, _ = strings.Cut(v, "+")
+
+	c, p, hasPre := strings.Cut(v, "-")
+	core = strings.Split(c, ".")
+	for _, n := range core {
+		if !isDigits(n) {
+			return nil, nil, false
+		}
+	}
+	if hasPre {
+		pre = strings.Split(p, ".")
+	}
+	return core, pre, true
+}
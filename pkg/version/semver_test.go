@@ -0,0 +1,28 @@
+package version
+
+import "testing"
+
+func TestSemver(t *testing.T) {
+	// precedence example from the SemVer 2.0 spec
+	assertOrdered(t, Semver, []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0",
+	})
+	assertOrdered(t, Semver, []string{
+		"0.0.0-20191109021931-daa7c04131f5", // Go pseudo-version
+		"v0.1.0", "v0.9.0", "v0.10.0", "v1.0.0-rc1", "v1.0.0", "v1.0.1", "v1.2.3",
+		"v2.0.0+incompatible", "10.0.0", "18446744073709551616.0.0", // beyond uint64
+	})
+	assertOrdered(t, Semver, []string{"1.0.0-1", "1.0.0-2", "1.0.0-10", "1.0.0-a", "1.0.0-a.1"})
+
+	assertEqual(t, Semver, [][2]string{
+		{"v1.2.3", "1.2.3"},
+		{"=1.2.3", "1.2.3"},
+		{"1.2", "1.2.0"},
+		{"1.0.0+build.1", "1.0.0+build.2"},
+		{"01.2.3", "1.2.3"},
+	})
+
+	// not semver: falls back to generic ordering
+	assertOrdered(t, Semver, []string{"2.0.0.RC1", "2.0.0.1"})
+}
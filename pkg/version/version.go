@@ -0,0 +1,132 @@
+// Package version orders package versions the way each ecosystem does.
+// Versions are never rejected: input a scheme cannot parse is compared with
+// the Generic rules so callers always get a total order.
+package version
+
+import (
+	"strings"
+)
+
+// Comparator orders two versions of the same scheme. Compare returns -1, 0
+// or +1 like strings.Compare.
+type Comparator interface {
+	Compare(a, b string) int
+}
+
+// Func adapts a plain function to Comparator.
+type Func func(a, b string) int
+
+func (f Func) Compare(a, b string) int { return f(a, b) }
+
+// scheme is a built-in comparator. Pointers keep them comparable with ==.
+type scheme struct {
+	name string
+	cmp  func(a, b string) int
+}
+
+func (s *scheme) Compare(a, b string) int { return s.cmp(a, b) }
+func (s *scheme) String() string          { return s.name }
+
+var (
+	Generic  Comparator = &scheme{"generic", compareGeneric}
+	Semver   Comparator = &scheme{"semver", compareSemver}
+	PEP440   Comparator = &scheme{"pep440", comparePEP440}
+	Maven    Comparator = &scheme{"maven", compareMaven}
+	RubyGems Comparator = &scheme{"rubygems", compareRubyGems}
+	Debian   Comparator = &scheme{"debian", compareDebian}
+	RPM      Comparator = &scheme{"rpm", compareRPM}
+	APK      Comparator = &scheme{"apk", compareAPK}
+)
+
+// purlTypes maps PURL types to their scheme.
+var purlTypes = map[string]Comparator{
+	"npm":    Semver,
+	"golang": Semver,
+	"cargo":  Semver,
+	"hex":    Semver,
+	"pub":    Semver,
+	"nuget":  Semver,
+	"swift":  Semver,
+	"pypi":   PEP440,
+	"maven":  Maven,
+	"gem":    RubyGems,
+	"deb":    Debian,
+	"rpm":    RPM,
+	"apk":    APK,
+}
+
+// packageTypes maps Syft package types (NormalizedPackage.Type) to their scheme.
+var packageTypes = map[string]Comparator{
+	"npm":            Semver,
+	"go-module":      Semver,
+	"rust-crate":     Semver,
+	"hex":            Semver,
+	"dart-pub":       Semver,
+	"dotnet":         Semver,
+	"swift":          Semver,
+	"python":         PEP440,
+	"java-archive":   Maven,
+	"jenkins-plugin": Maven,
+	"gem":            RubyGems,
+	"deb":            Debian,
+	"rpm":            RPM,
+	"apk":            APK,
+}
+
+// ForPURLType returns the comparator for a PURL type ("npm", "deb", ...).
+func ForPURLType(t string) Comparator {
+	if c, ok := purlTypes[strings.ToLower(t)]; ok {
+		return c
+	}
+	return Generic
+}
+
+// ForPackageType returns the comparator for a Syft package type.
+func ForPackageType(t string) Comparator {
+	if c, ok := packageTypes[strings.ToLower(t)]; ok {
+		return c
+	}
+	return Generic
+}
+
+// For picks a comparator from a package type, falling back to the PURL type.
+// This matches how NormalizedPackage carries both.
+func For(pkgType, purl string) Comparator {
+	if c, ok := packageTypes[strings.ToLower(pkgType)]; ok {
+		return c
+	}
+	return ForPURLType(purlType(purl))
+}
+
+// ForEcosystem returns the comparator for an OSV ecosystem, with or without
+// a release suffix ("Debian:12", "Alpine:v3.19").
+func ForEcosystem(eco string) Comparator {
+	base, _, _ := strings.Cut(eco, ":")
+	switch base {
+	case "npm", "Go", "crates.io", "Hex", "Pub", "NuGet", "SwiftURL":
+		return Semver
+	case "PyPI":
+		return PEP440
+	case "Maven":
+		return Maven
+	case "RubyGems":
+		return RubyGems
+	case "Debian", "Ubuntu":
+		return Debian
+	case "Alpine", "Wolfi", "Chainguard":
+		return APK
+	case "Red Hat", "Rocky Linux", "AlmaLinux", "openSUSE", "SUSE", "Mageia", "Photon OS":
+		return RPM
+	default:
+		return Generic
+	}
+}
+
+func purlType(purl string) string {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return ""
+	}
+	t, _, _ := strings.Cut(rest, "/")
+	return t
+}
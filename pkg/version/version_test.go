@@ -0,0 +1,65 @@
+package version
+
+import (
+	"testing"
+)
+
+// assertOrdered checks every pair of an ascending list in both directions.
+func assertOrdered(t *testing.T, c Comparator, versions []string) {
+	t.Helper()
+	for i, a := range versions {
+		if got := c.Compare(a, a); got != 0 {
+			t.Errorf("Compare(%q, %q) = %d, want 0", a, a, got)
+		}
+		for _, b := range versions[i+1:] {
+			if got := c.Compare(a, b); got != -1 {
+				t.Errorf("Compare(%q, %q) = %d, want -1", a, b, got)
+			}
+			if got := c.Compare(b, a); got != 1 {
+				t.Errorf("Compare(%q, %q) = %d, want 1", b, a, got)
+			}
+		}
+	}
+}
+
+func assertEqual(t *testing.T, c Comparator, pairs [][2]string) {
+	t.Helper()
+	for _, p := range pairs {
+		if got := c.Compare(p[0], p[1]); got != 0 {
+			t.Errorf("Compare(%q, %q) = %d, want 0", p[0], p[1], got)
+		}
+		if got := c.Compare(p[1], p[0]); got != 0 {
+			t.Errorf("Compare(%q, %q) = %d, want 0", p[1], p[0], got)
+		}
+	}
+}
+
+func TestSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Comparator
+		want Comparator
+	}{
+		{"python type", For("python", ""), PEP440},
+		{"go-module type", For("go-module", "pkg:golang/x@v1"), Semver},
+		{"type wins over purl", For("deb", "pkg:npm/x@1"), Debian},
+		{"purl fallback", For("", "pkg:rpm/redhat/openssl@1:3.0.7-1.el9"), RPM},
+		{"unknown", For("binary", "pkg:generic/x@1"), Generic},
+		{"purl type", ForPURLType("gem"), RubyGems},
+		{"syft type", ForPackageType("java-archive"), Maven},
+		{"ecosystem with release", ForEcosystem("Alpine:v3.19"), APK},
+		{"ubuntu", ForEcosystem("Ubuntu:22.04:LTS"), Debian},
+		{"crates.io", ForEcosystem("crates.io"), Semver},
+		{"rocky", ForEcosystem("Rocky Linux:9"), RPM},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestGeneric(t *testing.T) {
+	assertOrdered(t, Generic, []string{"0.9", "1.0", "1.0.rc1", "1.0.1", "1.2", "1.10", "2", "20240101"})
+	assertEqual(t, Generic, [][2]string{{"v1.2", "1.2"}, {"1-2", "1.2"}, {"1.0RC1", "1.0rc1"}})
+}
@@ -0,0 +1,69 @@
+package vex
+
+import (
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+var justifications = []Justification{
+	JustificationComponentNotPresent,
+	JustificationVulnerableCodeNotPresent,
+	JustificationVulnerableCodeNotInExecutePath,
+	JustificationVulnerableCodeCannotBeControlledByAdversary,
+	JustificationInlineMitigationsAlreadyExist,
+}
+
+// reasonHints maps common free-text triage phrases to a justification.
+// Order matters: more specific phrases come first.
+var reasonHints = []struct {
+	phrase        string
+	justification Justification
+}{
+	{"code not present", JustificationVulnerableCodeNotPresent},
+	{"not compiled", JustificationVulnerableCodeNotPresent},
+	{"not in execute path", JustificationVulnerableCodeNotInExecutePath},
+	{"not reachable", JustificationVulnerableCodeNotInExecutePath},
+	{"unreachable", JustificationVulnerableCodeNotInExecutePath},
+	{"not called", JustificationVulnerableCodeNotInExecutePath},
+	{"not used", JustificationVulnerableCodeNotInExecutePath},
+	{"cannot be controlled", JustificationVulnerableCodeCannotBeControlledByAdversary},
+	{"not exploitable", JustificationVulnerableCodeCannotBeControlledByAdversary},
+	{"mitigat", JustificationInlineMitigationsAlreadyExist},
+	{"not present", JustificationComponentNotPresent},
+	{"not installed", JustificationComponentNotPresent},
+}
+
+// ParseJustification accepts an OpenVEX justification label, tolerating
+// case, spaces and dashes ("Vulnerable code not present").
+func ParseJustification(s string) (Justification, bool) {
+	norm := strings.ToLower(strings.TrimSpace(s))
+	norm = strings.NewReplacer(" ", "_", "-", "_").Replace(norm)
+	for _, j := range justifications {
+		if norm == string(j) {
+			return j, true
+		}
+	}
+	return "", false
+}
+
+// JustificationFor picks the VEX justification for an ignore rule.
+// An explicit justification wins; otherwise we look for a label or a
+// well-known phrase in the reason. Returns "" when nothing matches, in
+// which case the reason is only usable as an impact statement.
+func JustificationFor(rule vuln.IgnoreRule) Justification {
+	if j, ok := ParseJustification(rule.Justification); ok {
+		return j
+	}
+	if j, ok := ParseJustification(rule.Reason); ok {
+		return j
+	}
+
+	reason := strings.ToLower(rule.Reason)
+	for _, h := range reasonHints {
+		if strings.Contains(reason, h.phrase) {
+			return h.justification
+		}
+	}
+	return ""
+}
@@ -0,0 +1,206 @@
+package vex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+const defaultActionStatement = "Upgrade the affected packages once a fixed version is available"
+
+// Options describe the product the VEX document is about.
+type Options struct {
+	// ImageRef is the image reference as given by the user (tag or digest).
+	ImageRef string
+
+	// Digest is the resolved manifest digest (sha256:...). Statements are
+	// always bound to the digest, never to a mutable tag.
+	Digest string
+
+	Author string
+
+	// Timestamp defaults to time.Now() when zero.
+	Timestamp time.Time
+}
+
+// Build produces an OpenVEX document for an image from scan findings and
+// triage decisions. Findings with a matching ignore rule become
+// not_affected; everything else is reported as affected.
+//
+// Findings must be the unfiltered scan results: filtering ignored findings
+// first would drop exactly the statements we want to publish.
+func Build(findings []vuln.Finding, ignore *vuln.IgnoreFile, opts Options) (*Document, error) {
+	if opts.Digest == "" {
+		return nil, fmt.Errorf("vex: image digest is required")
+	}
+	product, err := imageProduct(opts.ImageRef, opts.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := map[string]vuln.IgnoreRule{}
+	if ignore != nil {
+		for _, r := range ignore.Ignore {
+			rules[r.VulnID] = r
+		}
+	}
+
+	type group struct {
+		aliases map[string]struct{}
+		pkgs    map[string]struct{}
+	}
+	groups := map[string]*group{}
+	for _, f := range findings {
+		g, ok := groups[f.VulnID]
+		if !ok {
+			g = &group{aliases: map[string]struct{}{}, pkgs: map[string]struct{}{}}
+			groups[f.VulnID] = g
+		}
+		for _, a := range f.Aliases {
+			g.aliases[a] = struct{}{}
+		}
+		if id := packageID(f); id != "" {
+			g.pkgs[id] = struct{}{}
+		}
+	}
+
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	statements := make([]Statement, 0, len(ids))
+	for _, id := range ids {
+		g := groups[id]
+
+		p := product
+		for _, s := range sortedKeys(g.pkgs) {
+			p.Subcomponents = append(p.Subcomponents, Subcomponent{ID: s})
+		}
+
+		st := Statement{
+			Vulnerability: Vulnerability{Name: id, Aliases: sortedKeys(g.aliases)},
+			Products:      []Product{p},
+		}
+
+		if rule, ok := ruleFor(rules, id, st.Vulnerability.Aliases); ok {
+			st.Status = StatusNotAffected
+			st.Justification = JustificationFor(rule)
+			st.ImpactStatement = strings.TrimSpace(rule.Reason)
+			if st.Justification == "" && st.ImpactStatement == "" {
+				// OpenVEX requires one of the two for not_affected.
+				st.ImpactStatement = "Marked as not affected during triage"
+			}
+		} else {
+			st.Status = StatusAffected
+			st.ActionStatement = defaultActionStatement
+		}
+		statements = append(statements, st)
+	}
+
+	ts := opts.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	author := opts.Author
+	if author == "" {
+		author = "provavalidator"
+	}
+
+	doc := &Document{
+		Context:    Context,
+		Author:     author,
+		Timestamp:  ts.UTC(),
+		Version:    1,
+		Tooling:    "provavalidator",
+		Statements: statements,
+	}
+
+	// Content-addressed ID so re-running on the same inputs is stable.
+	b, err := json.Marshal(statements)
+	if err != nil {
+		return nil, fmt.Errorf("vex: marshal statements: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(opts.Digest), b...))
+	doc.ID = "https://openvex.dev/docs/public/vex-" + hex.EncodeToString(sum[:])
+
+	return doc, nil
+}
+
+// imageProduct identifies the image by an OCI purl pinned to its digest.
+// e.g. pkg:oci/nginx@sha256%3Aabc...?repository_url=index.docker.io/library/nginx
+func imageProduct(imageRef, digest string) (Product, error) {
+	hash := strings.TrimPrefix(digest, "sha256:")
+	if hash == digest || hash == "" {
+		return Product{}, fmt.Errorf("vex: unsupported digest %q (expected sha256:...)", digest)
+	}
+
+	p := Product{
+		ID:     digest,
+		Hashes: map[string]string{"sha-256": hash},
+	}
+	if imageRef == "" {
+		return p, nil
+	}
+
+	ref, err := name.ParseReference(imageRef)
+	if err != nil {
+		return Product{}, fmt.Errorf("vex: parse image ref: %w", err)
+	}
+	repo := ref.Context().Name()
+	purl := fmt.Sprintf("pkg:oci/%s@%s?repository_url=%s",
+		path.Base(ref.Context().RepositoryStr()),
+		url.QueryEscape(digest),
+		repo,
+	)
+
+	p.ID = purl
+	p.Identifiers = map[string]string{"purl": purl}
+	return p, nil
+}
+
+func packageID(f vuln.Finding) string {
+	if f.PURL != "" {
+		return f.PURL
+	}
+	if f.PackageVersion != "" {
+		return f.PackageName + "@" + f.PackageVersion
+	}
+	return f.PackageName
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ruleFor finds the ignore rule for a vulnerability by its ID or, since
+// findings are reported under their CVE, by any of its aliases.
+func ruleFor(rules map[string]vuln.IgnoreRule, id string, aliases []string) (vuln.IgnoreRule, bool) {
+	if r, ok := rules[id]; ok {
+		return r, true
+	}
+	for _, a := range aliases {
+		if r, ok := rules[a]; ok {
+			return r, true
+		}
+	}
+	return vuln.IgnoreRule{}, false
+}
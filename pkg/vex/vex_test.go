@@ -0,0 +1,75 @@
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/vuln"
+)
+
+func TestBuild_MapsIgnoreRulesToStatements(t *testing.T) {
+	findings := []vuln.Finding{
+		{PackageName: "openssl", PackageVersion: "3.0.2", PURL: "pkg:deb/debian/openssl@3.0.2", VulnID: "CVE-2024-0001"},
+		{PackageName: "libssl3", PackageVersion: "3.0.2", PURL: "pkg:deb/debian/libssl3@3.0.2", VulnID: "CVE-2024-0001"},
+		{PackageName: "jinja2", PackageVersion: "3.1.4", PURL: "pkg:pypi/jinja2@3.1.4", VulnID: "GHSA-xxxx", Aliases: []string{"CVE-2024-0002"}},
+		{PackageName: "zlib", PackageVersion: "1.2.13", VulnID: "CVE-2024-0003"},
+	}
+	ignore := &vuln.IgnoreFile{Ignore: []vuln.IgnoreRule{
+		{VulnID: "CVE-2024-0001", Reason: "TLS is terminated at the ingress; vulnerable code not in execute path"},
+		{VulnID: "GHSA-xxxx", Reason: "accepted risk"},
+	}}
+
+	doc, err := Build(findings, ignore, Options{
+		ImageRef:  "ghcr.io/acme/app:1.0",
+		Digest:    "sha256:0123456789abcdef",
+		Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	if len(doc.Statements) != 3 {
+		t.Fatalf("expected 3 statements (one per vuln), got %d", len(doc.Statements))
+	}
+
+	byID := map[string]Statement{}
+	for _, s := range doc.Statements {
+		byID[s.Vulnerability.Name] = s
+	}
+
+	s := byID["CVE-2024-0001"]
+	if s.Status != StatusNotAffected || s.Justification != JustificationVulnerableCodeNotInExecutePath {
+		t.Fatalf("unexpected status/justification: %s/%s", s.Status, s.Justification)
+	}
+	if got := len(s.Products[0].Subcomponents); got != 2 {
+		t.Fatalf("expected 2 subcomponents, got %d", got)
+	}
+	if s.Products[0].ID != "pkg:oci/app@sha256%3A0123456789abcdef?repository_url=ghcr.io/acme/app" {
+		t.Fatalf("unexpected product id %q", s.Products[0].ID)
+	}
+
+	s = byID["GHSA-xxxx"]
+	if s.Status != StatusNotAffected || s.Justification != "" || s.ImpactStatement != "accepted risk" {
+		t.Fatalf("expected impact statement fallback, got %+v", s)
+	}
+
+	s = byID["CVE-2024-0003"]
+	if s.Status != StatusAffected || s.ActionStatement == "" {
+		t.Fatalf("expected affected with action statement, got %+v", s)
+	}
+
+	again, _ := Build(findings, ignore, Options{ImageRef: "ghcr.io/acme/app:1.0", Digest: "sha256:0123456789abcdef"})
+	if again.ID != doc.ID {
+		t.Fatalf("expected stable document id, got %q and %q", doc.ID, again.ID)
+	}
+}
+
+func TestJustificationFor_ExplicitWins(t *testing.T) {
+	got := JustificationFor(vuln.IgnoreRule{
+		Reason:        "not reachable",
+		Justification: "Component not present",
+	})
+	if got != JustificationComponentNotPresent {
+		t.Fatalf("expected explicit justification, got %q", got)
+	}
+}
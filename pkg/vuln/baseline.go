@@ -0,0 +1,30 @@
+package vuln
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadBaseline reads the findings from an earlier `vuln --format json`
+// report (or a `check --format json` report, which has the same field).
+func LoadBaseline(path string) ([]Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("vuln: read baseline: %w", err)
+	}
+
+	var doc struct {
+		Findings *[]Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("vuln: parse baseline %s: %w", path, err)
+	}
+	if doc.Findings == nil {
+		return nil, fmt.Errorf("vuln: baseline %s has no findings field; expected a JSON report", path)
+	}
+	if *doc.Findings == nil {
+		return []Finding{}, nil
+	}
+	return *doc.Findings, nil
+}
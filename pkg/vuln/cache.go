@@ -0,0 +1,174 @@
+package vuln
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long OSV answers are reused before asking again.
+const DefaultCacheTTL = 6 * time.Hour
+
+// Cache kinds, one subdirectory each.
+const (
+	cacheQueries = "queries"
+	cacheVulns   = "vulns"
+)
+
+// Cache is a content-addressed on-disk store for OSV query results and
+// vulnerability records. Entries are files named by the SHA-256 of their
+// key and expire after TTL. Writes go through a rename, so concurrent
+// scans never see partial entries.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	StoredAt time.Time       `json:"storedAt"`
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// DefaultCacheDir is where the cache lives unless told otherwise.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("osv cache: locate cache dir: %w", err)
+	}
+	return filepath.Join(dir, "provavalidator", "osv-cache"), nil
+}
+
+// OpenCache prepares a cache in dir (DefaultCacheDir when empty). A zero
+// ttl uses DefaultCacheTTL.
+func OpenCache(dir string, ttl time.Duration) (*Cache, error) {
+	if dir == "" {
+		d, err := DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = d
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("osv cache: create dir: %w", err)
+	}
+	return &Cache{Dir: dir, TTL: ttl, now: time.Now}, nil
+}
+
+func (c *Cache) path(kind, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	h := hex.EncodeToString(sum[:])
+	return filepath.Join(c.Dir, kind, h[:2], h+".json")
+}
+
+// get decodes a fresh entry into v. Missing, expired or corrupt entries
+// are misses.
+func (c *Cache) get(kind, key string, v any) bool {
+	b, err := os.ReadFile(c.path(kind, key))
+	if err != nil {
+		return false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil || e.Key != key {
+		return false
+	}
+	if c.expired(e) {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// put stores v under key. Errors are returned for tests; callers treat
+// the cache as best effort.
+func (c *Cache) put(kind, key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("osv cache: marshal: %w", err)
+	}
+	b, err := json.Marshal(cacheEntry{StoredAt: c.now().UTC(), Key: key, Value: value})
+	if err != nil {
+		return fmt.Errorf("osv cache: marshal: %w", err)
+	}
+
+	path := c.path(kind, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("osv cache: create dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("osv cache: write: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("osv cache: write: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("osv cache: write: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("osv cache: write: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) expired(e cacheEntry) bool {
+	return c.now().Sub(e.StoredAt) > c.TTL
+}
+
+// Prune deletes expired entries, or every entry when all is set, and
+// returns how many were removed. Unreadable entries are always removed.
+func (c *Cache) Prune(all bool) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(c.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		if !all {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var e cacheEntry
+			if json.Unmarshal(b, &e) == nil && !c.expired(e) {
+				return nil
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("osv cache: prune: %w", err)
+	}
+	return removed, nil
+}
+
+// queryCacheKey identifies a query by content; the page token is transport
+// state, not part of the question.
+func queryCacheKey(q osvQuery) string {
+	q.PageToken = ""
+	b, _ := json.Marshal(q)
+	return string(b)
+}
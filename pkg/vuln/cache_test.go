@@ -0,0 +1,102 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetPutExpiry(t *testing.T) {
+	c, err := OpenCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	if err := c.put(cacheVulns, "GHSA-1", osvVulnerability{ID: "GHSA-1", Summary: "s"}); err != nil {
+		t.Fatal(err)
+	}
+	var v osvVulnerability
+	if !c.get(cacheVulns, "GHSA-1", &v) || v.Summary != "s" {
+		t.Fatalf("expected hit, got %+v", v)
+	}
+	if c.get(cacheQueries, "GHSA-1", &v) {
+		t.Fatal("kinds must not share entries")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if c.get(cacheVulns, "GHSA-1", &v) {
+		t.Fatal("expected expired entry to miss")
+	}
+
+	if err := c.put(cacheVulns, "GHSA-2", osvVulnerability{ID: "GHSA-2"}); err != nil {
+		t.Fatal(err)
+	}
+	n, err := c.Prune(false)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 expired entry pruned, got %d (%v)", n, err)
+	}
+	if !c.get(cacheVulns, "GHSA-2", &v) {
+		t.Fatal("fresh entry should survive prune")
+	}
+	if n, _ := c.Prune(true); n != 1 {
+		t.Fatalf("expected prune --all to remove 1 entry, got %d", n)
+	}
+}
+
+func TestOSVClient_UsesCache(t *testing.T) {
+	var batches, fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fetches.Add(1)
+			_ = json.NewEncoder(w).Encode(osvVulnerability{ID: "OSV-1", Summary: "cached"})
+			return
+		}
+		batches.Add(1)
+		var req osvQueryBatchRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := osvQueryBatchResponse{Results: make([]osvQueryResult, len(req.Queries))}
+		for i := range req.Queries {
+			resp.Results[i] = osvQueryResult{Vulns: []osvVulnerability{{ID: "OSV-1"}}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	cache, err := OpenCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &OSVClient{BaseURl: srv.URL, Cache: cache}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		res, err := client.QueryBatch(ctx, purlQueries(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 3 || res[2].Vulns[0].ID != "OSV-1" {
+			t.Fatalf("unexpected results: %+v", res)
+		}
+		recs, err := client.HydrateVulns(ctx, []string{"OSV-1"})
+		if err != nil || recs["OSV-1"].Summary != "cached" {
+			t.Fatalf("unexpected records: %+v (%v)", recs, err)
+		}
+	}
+	if batches.Load() != 1 || fetches.Load() != 1 {
+		t.Fatalf("expected second round from cache, got %d batches / %d fetches", batches.Load(), fetches.Load())
+	}
+
+	// only the new query goes to the network
+	if _, err := client.QueryBatch(ctx, purlQueries(4)); err != nil {
+		t.Fatal(err)
+	}
+	if batches.Load() != 2 {
+		t.Fatalf("expected one more batch for the uncached query, got %d", batches.Load())
+	}
+}
@@ -0,0 +1,160 @@
+package vuln
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+)
+
+// SkipReason says why a package could not be checked for vulnerabilities.
+// Reasons are a small fixed set so they can be counted and alerted on.
+type SkipReason string
+
+const (
+	SkipMissingVersion       SkipReason = "missing version"
+	SkipMissingPURL          SkipReason = "missing purl"
+	SkipInvalidPURL          SkipReason = "invalid purl"
+	SkipUnsupportedEcosystem SkipReason = "unsupported ecosystem"
+	SkipUnknownDistro        SkipReason = "unknown distro"
+)
+
+// SkippedPackage is a cataloged package the scan could not query.
+type SkippedPackage struct {
+	Name    string     `json:"name"`
+	Version string     `json:"version,omitempty"`
+	Type    string     `json:"type,omitempty"`
+	PURL    string     `json:"purl,omitempty"`
+	Reason  SkipReason `json:"reason"`
+	Detail  string     `json:"detail,omitempty"`
+}
+
+// Coverage records how much of the SBOM the scan actually looked at, so a
+// clean result can be told apart from one where nothing could be queried.
+type Coverage struct {
+	Packages        int                          `json:"packages"`
+	Queryable       int                          `json:"queryable"`
+	Percent         float64                      `json:"percent"`
+	SkippedByReason map[SkipReason]int           `json:"skippedByReason,omitempty"`
+	Ecosystems      map[string]EcosystemCoverage `json:"ecosystems,omitempty"`
+	Skipped         []SkippedPackage             `json:"skipped,omitempty"`
+
+	// Where the SBOM came from; empty when the caller scanned packages directly.
+	SBOMSource sbom.SourceType `json:"sbomSource,omitempty"`
+	SBOMFormat string          `json:"sbomFormat,omitempty"`
+}
+
+// EcosystemCoverage counts packages of one type (npm, deb, go-module, ...).
+type EcosystemCoverage struct {
+	Packages  int `json:"packages"`
+	Queryable int `json:"queryable"`
+	Skipped   int `json:"skipped"`
+}
+
+// unknownEcosystem groups packages Syft could not type.
+const unknownEcosystem = "unknown"
+
+// ScanResult is what a scan found plus what it could not check.
+type ScanResult struct {
+	Findings []Finding
+	Coverage Coverage
+
+	// SBOM is the SBOM that was scanned; nil when the caller scanned
+	// packages directly.
+	SBOM *sbom.ResolvedSBOM
+}
+
+// skipError explains why a package is not queryable. It is not a scan
+// failure; callers record it in Coverage and move on.
+type skipError struct {
+	reason SkipReason
+	detail string
+}
+
+func (e *skipError) Error() string {
+	if e.detail == "" {
+		return string(e.reason)
+	}
+	return fmt.Sprintf("%s: %s", e.reason, e.detail)
+}
+
+func skip(reason SkipReason, format string, args ...any) error {
+	return &skipError{reason: reason, detail: fmt.Sprintf(format, args...)}
+}
+
+func newCoverage() Coverage {
+	return Coverage{Percent: 100}
+}
+
+func coverageKey(p sbom.NormalizedPackage) string {
+	if p.Type == "" {
+		return unknownEcosystem
+	}
+	return p.Type
+}
+
+func (c *Coverage) update(p sbom.NormalizedPackage, queried bool) {
+	if c.Ecosystems == nil {
+		c.Ecosystems = map[string]EcosystemCoverage{}
+	}
+	eco := c.Ecosystems[coverageKey(p)]
+	eco.Packages++
+	c.Packages++
+	if queried {
+		eco.Queryable++
+		c.Queryable++
+	} else {
+		eco.Skipped++
+	}
+	c.Ecosystems[coverageKey(p)] = eco
+	c.Percent = c.percent()
+}
+
+// queried records a package that was sent to OSV (or the local database).
+func (c *Coverage) queried(p sbom.NormalizedPackage) {
+	c.update(p, true)
+}
+
+// percent is the share of cataloged packages that were queried. An empty
+// SBOM counts as fully covered: there was nothing to miss.
+func (c *Coverage) percent() float64 {
+	if c.Packages == 0 {
+		return 100
+	}
+	return math.Round(float64(c.Queryable)/float64(c.Packages)*1000) / 10
+}
+
+func (c *Coverage) skip(p sbom.NormalizedPackage, err error) {
+	c.update(p, false)
+	s := SkippedPackage{
+		Name:    p.Name,
+		Version: p.Version,
+		Type:    p.Type,
+		PURL:    p.PURL,
+	}
+	var se *skipError
+	if errors.As(err, &se) {
+		s.Reason, s.Detail = se.reason, se.detail
+	} else {
+		s.Reason, s.Detail = SkipUnsupportedEcosystem, err.Error()
+	}
+	c.Skipped = append(c.Skipped, s)
+
+	if c.SkippedByReason == nil {
+		c.SkippedByReason = map[SkipReason]int{}
+	}
+	c.SkippedByReason[s.Reason]++
+}
+
+// EnforceCoverage fails when fewer than min percent of the cataloged
+// packages could be checked. A min of 0 disables the check.
+func EnforceCoverage(c Coverage, min float64) error {
+	if min <= 0 || c.Percent >= min {
+		return nil
+	}
+	return fmt.Errorf(
+		"coverage policy violation: %.1f%% of packages scanned (%d/%d), minimum is %.1f%%",
+		c.Percent, c.Queryable, c.Packages, min,
+	)
+}
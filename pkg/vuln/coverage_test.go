@@ -0,0 +1,71 @@
+package vuln
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+)
+
+func TestCoverage_Counts(t *testing.T) {
+	pkgs := []sbom.NormalizedPackage{
+		{Name: "a", Version: "1.0", Type: "npm", PURL: "pkg:npm/a@1.0"},
+		{Name: "b", Version: "2.0", Type: "npm", PURL: "pkg:npm/b@2.0"},
+		{Name: "c", Type: "npm", PURL: "pkg:npm/c"},
+		{Name: "d", Version: "1.0", Type: "binary"},
+		{Name: "e", Version: "1.0"},
+	}
+
+	// offline scan against an empty database exercises the same bookkeeping
+	// without a network
+	db, err := OpenLocalDB(t.TempDir() + "/osv.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	res, err := ScanNormalizedPackagesOffline(context.Background(), db, pkgs, DefaultScanOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := res.Coverage
+
+	if c.Packages != 5 || c.Queryable != 2 || c.Percent != 40 {
+		t.Fatalf("unexpected totals: %+v", c)
+	}
+	if c.SkippedByReason[SkipMissingVersion] != 1 || c.SkippedByReason[SkipUnsupportedEcosystem] != 2 {
+		t.Fatalf("unexpected reasons: %v", c.SkippedByReason)
+	}
+	if got := c.Ecosystems["npm"]; got != (EcosystemCoverage{Packages: 3, Queryable: 2, Skipped: 1}) {
+		t.Fatalf("unexpected npm coverage: %+v", got)
+	}
+	if got := c.Ecosystems[unknownEcosystem]; got.Packages != 1 || got.Skipped != 1 {
+		t.Fatalf("unexpected unknown coverage: %+v", got)
+	}
+
+	if err := EnforceCoverage(c, 0); err != nil {
+		t.Fatalf("disabled policy should pass: %v", err)
+	}
+	if err := EnforceCoverage(c, 40); err != nil {
+		t.Fatalf("40%% should satisfy 40%%: %v", err)
+	}
+	if err := EnforceCoverage(c, 90); err == nil {
+		t.Fatal("expected coverage violation")
+	}
+}
+
+func TestCoverage_Empty(t *testing.T) {
+	db, err := OpenLocalDB(t.TempDir() + "/osv.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	res, err := ScanNormalizedPackagesOffline(context.Background(), db, nil, DefaultScanOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Coverage.Percent != 100 {
+		t.Fatalf("expected empty SBOM to be fully covered, got %.1f", res.Coverage.Percent)
+	}
+}
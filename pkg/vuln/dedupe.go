@@ -0,0 +1,194 @@
+package vuln
+
+import (
+	"sort"
+	"strings"
+)
+
+// Deduplicate merges findings that describe the same issue in the same
+// package version. Findings are the same issue when their IDs or aliases
+// connect them, directly or through other findings (GHSA-a aliases CVE-1,
+// and PYSEC-b aliases GHSA-a). The same package cataloged at several paths
+// is merged too, with every path kept in Locations.
+//
+// The merged finding takes a CVE ID when the group has one and lists every
+// other ID as an alias. It keeps the worst severity, the union of fixed
+// versions and locations, and otherwise the order of first appearance.
+func Deduplicate(findings []Finding) []Finding {
+	// group by package version first; aliases only merge within a package
+	var order []string
+	byPkg := map[string][]int{}
+	for i, f := range findings {
+		k := packageIdentity(f) + "@" + f.PackageVersion
+		if _, ok := byPkg[k]; !ok {
+			order = append(order, k)
+		}
+		byPkg[k] = append(byPkg[k], i)
+	}
+
+	out := make([]Finding, 0, len(findings))
+	for _, k := range order {
+		for _, group := range aliasGroups(findings, byPkg[k]) {
+			out = append(out, mergeFindings(findings, group))
+		}
+	}
+	return out
+}
+
+// aliasGroups partitions the findings at idx into connected components of
+// the alias graph, in order of first appearance.
+func aliasGroups(findings []Finding, idx []int) [][]int {
+	parent := make([]int, len(idx))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	owner := map[string]int{}
+	for i, fi := range idx {
+		for _, id := range findingIDs(findings[fi]) {
+			if j, ok := owner[id]; ok {
+				a, b := find(i), find(j)
+				if a != b {
+					parent[max(a, b)] = min(a, b)
+				}
+			} else {
+				owner[id] = i
+			}
+		}
+	}
+
+	var groups [][]int
+	pos := map[int]int{}
+	for i, fi := range idx {
+		r := find(i)
+		p, ok := pos[r]
+		if !ok {
+			p = len(groups)
+			pos[r] = p
+			groups = append(groups, nil)
+		}
+		groups[p] = append(groups[p], fi)
+	}
+	return groups
+}
+
+func mergeFindings(findings []Finding, group []int) Finding {
+	base := findings[group[0]]
+	for _, i := range group[1:] {
+		if worse(findings[i], base) {
+			base = findings[i]
+		}
+	}
+
+	out := base
+	out.Aliases = nil
+	out.FixedVersions = nil
+	out.Locations = nil
+	out.IntroducedVia = nil
+
+	ids := map[string]struct{}{}
+	fixed := map[string]struct{}{}
+	locs := map[string]struct{}{}
+	via := map[string]struct{}{}
+	var canonical string
+	for _, i := range group {
+		f := findings[i]
+		if canonical == "" && isCVE(f.VulnID) {
+			canonical = f.VulnID
+		}
+		ids[f.VulnID] = struct{}{}
+		for _, a := range f.Aliases {
+			ids[a] = struct{}{}
+		}
+		for _, v := range f.FixedVersions {
+			if _, ok := fixed[v]; !ok {
+				fixed[v] = struct{}{}
+				out.FixedVersions = append(out.FixedVersions, v)
+			}
+		}
+		for _, l := range f.Locations {
+			locs[l] = struct{}{}
+		}
+		for _, v := range f.IntroducedVia {
+			if _, ok := via[v]; !ok {
+				via[v] = struct{}{}
+				out.IntroducedVia = append(out.IntroducedVia, v)
+			}
+		}
+		if out.Summary == "" {
+			out.Summary = f.Summary
+		}
+		if out.Details == "" {
+			out.Details = f.Details
+		}
+	}
+
+	all := sortedSet(ids)
+	if canonical == "" {
+		for _, id := range all {
+			if isCVE(id) {
+				canonical = id
+				break
+			}
+		}
+	}
+	if canonical == "" {
+		canonical = findings[group[0]].VulnID
+	}
+	out.VulnID = canonical
+	for _, id := range all {
+		if id != canonical {
+			out.Aliases = append(out.Aliases, id)
+		}
+	}
+
+	out.FixAvailable = len(out.FixedVersions) > 0
+	if len(locs) > 0 {
+		out.Locations = sortedSet(locs)
+	}
+	// each finding lists its chains shortest first, but the same package at
+	// another location has its own set; order the union by hops, ties in
+	// order of appearance, before capping it
+	sort.SliceStable(out.IntroducedVia, func(i, j int) bool {
+		return chainHops(out.IntroducedVia[i]) < chainHops(out.IntroducedVia[j])
+	})
+	if len(out.IntroducedVia) > maxIntroducedVia {
+		out.IntroducedVia = out.IntroducedVia[:maxIntroducedVia]
+	}
+	return out
+}
+
+// chainHops counts the packages in an IntroducedVia chain.
+func chainHops(chain string) int {
+	return strings.Count(chain, " > ") + 1
+}
+
+// worse reports whether a should be the base of a merge over b: higher
+// severity first, then higher CVSS score.
+func worse(a, b Finding) bool {
+	if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+		return ra > rb
+	}
+	return a.CVSSScore > b.CVSSScore
+}
+
+func isCVE(id string) bool {
+	return strings.HasPrefix(strings.ToUpper(id), "CVE-")
+}
+
+func sortedSet(m map[string]struct{}) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
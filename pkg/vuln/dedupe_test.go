@@ -0,0 +1,95 @@
+package vuln
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeduplicate(t *testing.T) {
+	in := []Finding{
+		{VulnID: "GHSA-aaaa", Aliases: []string{"CVE-2024-1"}, PackageName: "requests", PackageVersion: "2.31.0",
+			PURL: "pkg:pypi/requests@2.31.0", Severity: SeverityMedium, CVSSScore: 5.0, Summary: "from ghsa",
+			FixedVersions: []string{"2.32.0"}, Locations: []string{"/app/a/METADATA"}},
+		// linked to the GHSA only through PYSEC's alias list
+		{VulnID: "PYSEC-2024-9", Aliases: []string{"GHSA-aaaa"}, PackageName: "requests", PackageVersion: "2.31.0",
+			PURL: "pkg:pypi/requests@2.31.0", Severity: SeverityHigh, CVSSScore: 7.5,
+			FixedVersions: []string{"2.32.0", "2.31.1"}, Locations: []string{"/app/b/METADATA"}},
+		// same package at another path
+		{VulnID: "GHSA-aaaa", Aliases: []string{"CVE-2024-1"}, PackageName: "requests", PackageVersion: "2.31.0",
+			PURL: "pkg:pypi/requests@2.31.0", Severity: SeverityMedium, Locations: []string{"/app/a/METADATA", "/app/c/METADATA"}},
+		// unrelated issue in the same package
+		{VulnID: "GHSA-bbbb", PackageName: "requests", PackageVersion: "2.31.0", PURL: "pkg:pypi/requests@2.31.0", Severity: SeverityLow},
+		// same issue in another version is a separate finding
+		{VulnID: "GHSA-aaaa", Aliases: []string{"CVE-2024-1"}, PackageName: "requests", PackageVersion: "2.30.0",
+			PURL: "pkg:pypi/requests@2.30.0", Severity: SeverityMedium},
+	}
+
+	out := Deduplicate(in)
+	if len(out) != 3 {
+		t.Fatalf("expected 3 findings, got %d: %+v", len(out), out)
+	}
+
+	m := out[0]
+	if m.VulnID != "CVE-2024-1" {
+		t.Errorf("expected the CVE as canonical ID, got %s", m.VulnID)
+	}
+	if want := []string{"GHSA-aaaa", "PYSEC-2024-9"}; !reflect.DeepEqual(m.Aliases, want) {
+		t.Errorf("aliases = %v, want %v", m.Aliases, want)
+	}
+	if m.Severity != SeverityHigh || m.CVSSScore != 7.5 {
+		t.Errorf("expected the worst severity, got %s/%.1f", m.Severity, m.CVSSScore)
+	}
+	if m.Summary != "from ghsa" {
+		t.Errorf("expected summary filled from another record, got %q", m.Summary)
+	}
+	if want := []string{"2.32.0", "2.31.1"}; !reflect.DeepEqual(m.FixedVersions, want) || !m.FixAvailable {
+		t.Errorf("fixed = %v, want %v", m.FixedVersions, want)
+	}
+	if want := []string{"/app/a/METADATA", "/app/b/METADATA", "/app/c/METADATA"}; !reflect.DeepEqual(m.Locations, want) {
+		t.Errorf("locations = %v, want %v", m.Locations, want)
+	}
+
+	if out[1].VulnID != "GHSA-bbbb" || out[2].PackageVersion != "2.30.0" || out[2].VulnID != "CVE-2024-1" {
+		t.Errorf("unexpected remaining findings: %+v", out[1:])
+	}
+
+	if s := Summarize(out); s.Total != 3 || s.High != 1 || s.Medium != 1 || s.Low != 1 {
+		t.Errorf("unexpected summary after dedupe: %+v", s)
+	}
+
+	// ignore entries written against the GHSA still apply
+	if got := FilterIgnored(out, map[string]struct{}{"GHSA-aaaa": {}}); len(got) != 1 || got[0].VulnID != "GHSA-bbbb" {
+		t.Errorf("expected alias-aware ignore, got %+v", got)
+	}
+}
+
+func TestDeduplicate_IntroducedVia(t *testing.T) {
+	// the same urllib3 vendored at two paths: each copy has its own chains,
+	// which end at the direct parent, and the first copy only has long ones
+	in := []Finding{
+		{VulnID: "GHSA-aaaa", PackageName: "urllib3", PackageVersion: "1.26.0", Locations: []string{"/opt/aws/urllib3"},
+			IntroducedVia: []string{
+				"app 1.0 > boto3 1.34.0 > botocore 1.34.0",
+				"app 1.0 > awscli 1.32.0 > botocore 1.34.0",
+			}},
+		{VulnID: "PYSEC-2024-1", Aliases: []string{"GHSA-aaaa"}, PackageName: "urllib3", PackageVersion: "1.26.0", Locations: []string{"/app/urllib3"},
+			IntroducedVia: []string{
+				"app 1.0",
+				"app 1.0 > requests 2.31.0",
+				"app 1.0 > boto3 1.34.0 > botocore 1.34.0",
+			}},
+	}
+
+	out := Deduplicate(in)
+	if len(out) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(out), out)
+	}
+	want := []string{
+		"app 1.0",
+		"app 1.0 > requests 2.31.0",
+		"app 1.0 > boto3 1.34.0 > botocore 1.34.0",
+	}
+	if !reflect.DeepEqual(out[0].IntroducedVia, want) {
+		t.Fatalf("introducedVia = %v, want %v", out[0].IntroducedVia, want)
+	}
+}
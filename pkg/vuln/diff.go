@@ -0,0 +1,83 @@
+package vuln
+
+import (
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+)
+
+// Delta classifies the findings of a scan against a baseline scan.
+type Delta struct {
+	New       []Finding `json:"new"`
+	Fixed     []Finding `json:"fixed"`
+	Unchanged []Finding `json:"unchanged"`
+}
+
+// Compare matches findings in current against baseline. Two findings are
+// the same when they affect the same package (ignoring its version, so an
+// upgrade that does not fix the issue stays unchanged) and share the
+// vulnerability ID or any alias, so GHSA-x in one report matches CVE-y in
+// the other. Fixed holds baseline findings; New and Unchanged hold current ones.
+func Compare(baseline, current []Finding) Delta {
+	type key struct{ pkg, id string }
+
+	index := map[key][]int{}
+	for i, f := range baseline {
+		pkg := packageIdentity(f)
+		for _, id := range findingIDs(f) {
+			index[key{pkg, id}] = append(index[key{pkg, id}], i)
+		}
+	}
+
+	d := Delta{New: []Finding{}, Fixed: []Finding{}, Unchanged: []Finding{}}
+	matched := make([]bool, len(baseline))
+	for _, f := range current {
+		pkg := packageIdentity(f)
+		found := false
+	ids:
+		for _, id := range findingIDs(f) {
+			for _, i := range index[key{pkg, id}] {
+				if !matched[i] {
+					matched[i] = true
+					found = true
+					break ids
+				}
+			}
+		}
+		if found {
+			d.Unchanged = append(d.Unchanged, f)
+		} else {
+			d.New = append(d.New, f)
+		}
+	}
+
+	for i, f := range baseline {
+		if !matched[i] {
+			d.Fixed = append(d.Fixed, f)
+		}
+	}
+	return d
+}
+
+// FilterNew drops findings that are already in the baseline.
+func FilterNew(findings, baseline []Finding) []Finding {
+	return Compare(baseline, findings).New
+}
+
+// packageIdentity names a package without its version: the PURL's type,
+// namespace and name when there is one, else the package type and name.
+func packageIdentity(f Finding) string {
+	if p, err := packageurl.FromString(f.PURL); err == nil && f.PURL != "" {
+		return strings.ToLower(p.Type + "/" + p.Namespace + "/" + p.Name)
+	}
+	return strings.ToLower(f.PackageType + "//" + f.PackageName)
+}
+
+func findingIDs(f Finding) []string {
+	ids := make([]string, 0, 1+len(f.Aliases))
+	ids = append(ids, strings.ToUpper(f.VulnID))
+	for _, a := range f.Aliases {
+		ids = append(ids, strings.ToUpper(a))
+	}
+	return ids
+}
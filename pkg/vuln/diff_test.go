@@ -0,0 +1,51 @@
+package vuln
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	baseline := []Finding{
+		{VulnID: "GHSA-aaaa", Aliases: []string{"CVE-2024-1"}, PackageName: "requests", PackageVersion: "2.31.0", PURL: "pkg:pypi/requests@2.31.0"},
+		{VulnID: "CVE-2024-2", PackageName: "openssl", PackageType: "deb", PackageVersion: "3.0.11"},
+		{VulnID: "CVE-2024-3", PackageName: "zlib", PackageType: "deb", PackageVersion: "1.2.13"},
+	}
+	current := []Finding{
+		// same vuln reported under its CVE, after a version bump that did not fix it
+		{VulnID: "CVE-2024-1", PackageName: "requests", PackageVersion: "2.31.1", PURL: "pkg:pypi/requests@2.31.1"},
+		{VulnID: "CVE-2024-2", PackageName: "openssl", PackageType: "deb", PackageVersion: "3.0.13"},
+		// same ID, different package
+		{VulnID: "CVE-2024-2", PackageName: "libssl3", PackageType: "deb", PackageVersion: "3.0.13"},
+		{VulnID: "CVE-2024-4", PackageName: "curl", PackageType: "deb", PackageVersion: "8.0.0"},
+	}
+
+	d := Compare(baseline, current)
+
+	if got := ids(d.Unchanged); len(got) != 2 || got[0] != "CVE-2024-1/requests" || got[1] != "CVE-2024-2/openssl" {
+		t.Errorf("unexpected unchanged: %v", got)
+	}
+	if got := ids(d.New); len(got) != 2 || got[0] != "CVE-2024-2/libssl3" || got[1] != "CVE-2024-4/curl" {
+		t.Errorf("unexpected new: %v", got)
+	}
+	if got := ids(d.Fixed); len(got) != 1 || got[0] != "CVE-2024-3/zlib" {
+		t.Errorf("unexpected fixed: %v", got)
+	}
+
+	if got := FilterNew(current, baseline); len(got) != 2 {
+		t.Errorf("FilterNew should keep the new findings, got %v", ids(got))
+	}
+}
+
+func TestCompare_DuplicatesMatchOnce(t *testing.T) {
+	f := Finding{VulnID: "CVE-1", PackageName: "a", PackageType: "npm"}
+	d := Compare([]Finding{f}, []Finding{f, f})
+	if len(d.Unchanged) != 1 || len(d.New) != 1 || len(d.Fixed) != 0 {
+		t.Fatalf("unexpected delta: %+v", d)
+	}
+}
+
+func ids(fs []Finding) []string {
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		out[i] = f.VulnID + "/" + f.PackageName
+	}
+	return out
+}
@@ -0,0 +1,277 @@
+package vuln
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+)
+
+// purlEcosystems maps PURL types to OSV ecosystem names.
+// Distro package types (deb, apk, rpm) are resolved separately because the
+// ecosystem depends on the distro and release.
+// Ecosystems: https://ossf.github.io/osv-schema/#affectedpackage-field
+var purlEcosystems = map[string]string{
+	"npm":       "npm",
+	"pypi":      "PyPI",
+	"golang":    "Go",
+	"maven":     "Maven",
+	"cargo":     "crates.io",
+	"gem":       "RubyGems",
+	"nuget":     "NuGet",
+	"composer":  "Packagist",
+	"hex":       "Hex",
+	"pub":       "Pub",
+	"hackage":   "Hackage",
+	"cran":      "CRAN",
+	"swift":     "SwiftURL",
+	"conan":     "ConanCenter",
+	"bitnami":   "Bitnami",
+	"github":    "GitHub Actions",
+	"cocoapods": "CocoaPods",
+}
+
+// distroEcosystems maps a distro ID (from the PURL namespace or distro
+// qualifier) to its OSV ecosystem prefix.
+var distroEcosystems = map[string]string{
+	"debian":     "Debian",
+	"ubuntu":     "Ubuntu",
+	"alpine":     "Alpine",
+	"wolfi":      "Wolfi",
+	"chainguard": "Chainguard",
+	"rocky":      "Rocky Linux",
+	"almalinux":  "AlmaLinux",
+	"redhat":     "Red Hat",
+	"opensuse":   "openSUSE",
+	"sles":       "SUSE",
+	"suse":       "SUSE",
+	"mageia":     "Mageia",
+	"photon":     "Photon OS",
+}
+
+// osvCoordinates is a package expressed the way OSV records name it.
+type osvCoordinates struct {
+	Ecosystem string // e.g. "PyPI", "Debian:12", "Alpine:v3.19"
+	Name      string
+	Version   string
+}
+
+// packageTypeEcosystems maps Syft package types to OSV ecosystems. It is
+// the fallback for packages cataloged without a PURL.
+var packageTypeEcosystems = map[string]string{
+	"npm":            "npm",
+	"python":         "PyPI",
+	"go-module":      "Go",
+	"java-archive":   "Maven",
+	"jenkins-plugin": "Maven",
+	"rust-crate":     "crates.io",
+	"gem":            "RubyGems",
+	"php-composer":   "Packagist",
+	"dotnet":         "NuGet",
+	"hex":            "Hex",
+	"dart-pub":       "Pub",
+	"conan":          "ConanCenter",
+	"pod":            "CocoaPods",
+	"swift":          "SwiftURL",
+	"hackage":        "Hackage",
+	"R-package":      "CRAN",
+	"bitnami":        "Bitnami",
+}
+
+// coordinatesFromPURL maps a PURL to OSV ecosystem, name and version.
+// Distro packages use the source package name (upstream qualifier), which is
+// what Debian, Ubuntu and Alpine advisories are keyed by. When the PURL has
+// no distro qualifier, the release is taken from distro if it is the same OS.
+func coordinatesFromPURL(purl string, distro *sbom.Distro) (osvCoordinates, error) {
+	p, err := packageurl.FromString(purl)
+	if err != nil {
+		return osvCoordinates{}, skip(SkipInvalidPURL, "%v", err)
+	}
+	q := p.Qualifiers.Map()
+
+	c := osvCoordinates{Name: p.Name, Version: p.Version}
+
+	switch p.Type {
+	case "deb", "apk", "rpm":
+		id := strings.ToLower(p.Namespace)
+		release := ""
+		if d := q["distro"]; d != "" {
+			// distro qualifier looks like "debian-12" or "alpine-3.19.0"
+			qid, rel, _ := strings.Cut(d, "-")
+			if id == "" {
+				id = strings.ToLower(qid)
+			}
+			release = rel
+		} else if distro != nil && strings.EqualFold(distro.ID, id) {
+			release = distro.VersionID
+		}
+		eco, ok := distroEcosystems[id]
+		if !ok {
+			return osvCoordinates{}, skip(SkipUnknownDistro, "%q", id)
+		}
+		c.Ecosystem = distroEcosystem(eco, release)
+		if up := q["upstream"]; up != "" {
+			// syft writes "name" or "name@version"
+			name, _, _ := strings.Cut(up, "@")
+			c.Name = strings.TrimSpace(name)
+		}
+	default:
+		eco, ok := purlEcosystems[p.Type]
+		if !ok {
+			return osvCoordinates{}, skip(SkipUnsupportedEcosystem, "purl type %q", p.Type)
+		}
+		c.Ecosystem = eco
+		if p.Namespace != "" {
+			switch p.Type {
+			case "maven":
+				c.Name = p.Namespace + ":" + p.Name
+			case "npm", "golang", "composer", "github", "swift":
+				c.Name = p.Namespace + "/" + p.Name
+			}
+		}
+	}
+	return c, nil
+}
+
+// coordinatesFromType maps a package without a PURL using its Syft type.
+// OS packages need the image's distro to pick the release, and use the
+// binary package name since the source name is only carried in the PURL.
+func coordinatesFromType(p sbom.NormalizedPackage, distro *sbom.Distro) (osvCoordinates, error) {
+	c := osvCoordinates{Name: strings.TrimSpace(p.Name), Version: strings.TrimSpace(p.Version)}
+
+	switch p.Type {
+	case "deb", "apk", "rpm":
+		if distro == nil || distro.ID == "" {
+			return osvCoordinates{}, skip(SkipUnknownDistro, "no distro detected for %s package", p.Type)
+		}
+		eco, ok := distroEcosystems[strings.ToLower(distro.ID)]
+		if !ok {
+			return osvCoordinates{}, skip(SkipUnknownDistro, "%q", distro.ID)
+		}
+		c.Ecosystem = distroEcosystem(eco, distro.VersionID)
+	default:
+		eco, ok := packageTypeEcosystems[p.Type]
+		if !ok {
+			if p.Type == "" {
+				return osvCoordinates{}, skip(SkipUnsupportedEcosystem, "no package type")
+			}
+			return osvCoordinates{}, skip(SkipUnsupportedEcosystem, "package type %q", p.Type)
+		}
+		if eco == "Maven" && !strings.Contains(c.Name, ":") {
+			// OSV keys Maven by group:artifact and the bare name has no group
+			return osvCoordinates{}, skip(SkipMissingPURL, "maven artifact without group id")
+		}
+		c.Ecosystem = eco
+	}
+	if c.Name == "" {
+		return osvCoordinates{}, skip(SkipUnsupportedEcosystem, "no package name")
+	}
+	return c, nil
+}
+
+// distroEcosystem appends the release the way OSV spells it
+// (Debian:12, Alpine:v3.19, Ubuntu:22.04:LTS, Ubuntu:23.10). Without a
+// release we return the bare prefix and lookups match any release.
+func distroEcosystem(eco, release string) string {
+	if release == "" {
+		return eco
+	}
+	switch eco {
+	case "Alpine":
+		parts := strings.SplitN(release, ".", 3)
+		if len(parts) >= 2 {
+			release = parts[0] + "." + parts[1]
+		}
+		return eco + ":v" + strings.TrimPrefix(release, "v")
+	case "Ubuntu":
+		if ubuntuLTS(release) {
+			return eco + ":" + release + ":LTS"
+		}
+		return eco + ":" + release
+	case "Debian", "Rocky Linux", "AlmaLinux":
+		// major release only
+		major, _, _ := strings.Cut(release, ".")
+		return eco + ":" + major
+	default:
+		return eco + ":" + release
+	}
+}
+
+// ubuntuLTS reports whether an Ubuntu release is a long term support one:
+// the April release of even years (20.04, 22.04, 24.04).
+func ubuntuLTS(release string) bool {
+	year, month, ok := strings.Cut(release, ".")
+	if !ok || month != "04" {
+		return false
+	}
+	y, err := strconv.Atoi(year)
+	return err == nil && y%2 == 0
+}
+
+// packageCoordinates resolves a package the way the offline matcher needs
+// it: from the PURL when there is one, else from the package type.
+func packageCoordinates(p sbom.NormalizedPackage, opts ScanOptions) (osvCoordinates, error) {
+	var c osvCoordinates
+	var err error
+	switch {
+	case strings.TrimSpace(p.PURL) != "":
+		c, err = coordinatesFromPURL(p.PURL, opts.Distro)
+	case opts.RequirePURL:
+		return osvCoordinates{}, skip(SkipMissingPURL, "")
+	default:
+		c, err = coordinatesFromType(p, opts.Distro)
+	}
+	if err != nil {
+		return osvCoordinates{}, err
+	}
+	if c.Version == "" {
+		c.Version = strings.TrimSpace(p.Version)
+	}
+	if c.Version == "" {
+		return osvCoordinates{}, skip(SkipMissingVersion, "")
+	}
+	return c, nil
+}
+
+// normalizeName puts a package name in the form used to compare it with
+// the names in OSV records. Names are compared case-insensitively, and PyPI
+// names per PEP 503, where runs of "-", "_" and "." are equivalent.
+func normalizeName(ecosystem, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if ecosystem != "PyPI" {
+		return name
+	}
+	var b strings.Builder
+	sep := false
+	for _, r := range name {
+		if r == '-' || r == '_' || r == '.' {
+			sep = true
+			continue
+		}
+		if sep {
+			b.WriteByte('-')
+			sep = false
+		}
+		b.WriteRune(r)
+	}
+	if sep {
+		b.WriteByte('-')
+	}
+	return b.String()
+}
+
+// upstreamCoordinates returns c renamed to p's distro source package, for
+// packages whose PURL does not already name it. Distro advisories are
+// filed under the source package.
+func upstreamCoordinates(p sbom.NormalizedPackage, c osvCoordinates) (osvCoordinates, bool) {
+	if p.Upstream == nil || normalizeName(c.Ecosystem, p.Upstream.Name) == normalizeName(c.Ecosystem, c.Name) {
+		return osvCoordinates{}, false
+	}
+	u := c
+	u.Name = p.Upstream.Name
+	if p.Upstream.Version != "" {
+		u.Version = p.Upstream.Version
+	}
+	return u, true
+}
@@ -0,0 +1,274 @@
+package vuln
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KEVEntry is a vulnerability's record in the CISA Known Exploited
+// Vulnerabilities catalog.
+type KEVEntry struct {
+	DateAdded       string `json:"dateAdded,omitempty"`
+	DueDate         string `json:"dueDate,omitempty"`
+	RequiredAction  string `json:"requiredAction,omitempty"`
+	KnownRansomware bool   `json:"knownRansomware,omitempty"`
+}
+
+// EPSSScore is the FIRST Exploit Prediction Scoring System estimate that a
+// vulnerability will be exploited in the next 30 days.
+type EPSSScore struct {
+	Probability float64 `json:"probability"`
+	Percentile  float64 `json:"percentile"`
+	Date        string  `json:"date,omitempty"`
+}
+
+// KEVCatalog is a loaded KEV file, indexed by CVE ID.
+type KEVCatalog struct {
+	Version string
+	entries map[string]KEVEntry
+}
+
+// LoadKEV reads the catalog as published at
+// https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json.
+func LoadKEV(path string) (*KEVCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("vuln: read kev catalog: %w", err)
+	}
+
+	var doc struct {
+		CatalogVersion  string `json:"catalogVersion"`
+		Vulnerabilities []struct {
+			CVEID                      string `json:"cveID"`
+			DateAdded                  string `json:"dateAdded"`
+			DueDate                    string `json:"dueDate"`
+			RequiredAction             string `json:"requiredAction"`
+			KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("vuln: parse kev catalog %s: %w", path, err)
+	}
+	if doc.Vulnerabilities == nil {
+		return nil, fmt.Errorf("vuln: %s has no vulnerabilities field; expected the CISA KEV JSON feed", path)
+	}
+
+	c := &KEVCatalog{Version: doc.CatalogVersion, entries: make(map[string]KEVEntry, len(doc.Vulnerabilities))}
+	for _, v := range doc.Vulnerabilities {
+		c.entries[strings.ToUpper(v.CVEID)] = KEVEntry{
+			DateAdded:       v.DateAdded,
+			DueDate:         v.DueDate,
+			RequiredAction:  v.RequiredAction,
+			KnownRansomware: strings.EqualFold(v.KnownRansomwareCampaignUse, "known"),
+		}
+	}
+	return c, nil
+}
+
+func (c *KEVCatalog) Len() int { return len(c.entries) }
+
+// EPSSData is a loaded EPSS file, indexed by CVE ID.
+type EPSSData struct {
+	ModelVersion string
+	ScoreDate    string
+	scores       map[string]EPSSScore
+}
+
+// LoadEPSS reads the daily CSV from https://epss.cyentia.com/epss_scores-current.csv.gz,
+// gzipped or not. The file starts with a "#model_version:...,score_date:..."
+// comment followed by a cve,epss,percentile header.
+func LoadEPSS(path string) (*EPSSData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("vuln: read epss scores: %w", err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("vuln: read epss scores: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	d := &EPSSData{scores: map[string]EPSSScore{}}
+	lines := bufio.NewReader(r)
+
+	// metadata comment
+	if b, err := lines.Peek(1); err == nil && b[0] == '#' {
+		meta, err := lines.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("vuln: read epss scores: %w", err)
+		}
+		for _, kv := range strings.Split(strings.TrimSpace(strings.TrimPrefix(meta, "#")), ",") {
+			k, v, _ := strings.Cut(kv, ":")
+			switch k {
+			case "model_version":
+				d.ModelVersion = v
+			case "score_date":
+				d.ScoreDate = v
+			}
+		}
+	}
+
+	cr := csv.NewReader(lines)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("vuln: parse epss scores %s: %w", path, err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	cveCol, okC := col["cve"]
+	epssCol, okE := col["epss"]
+	pctCol, okP := col["percentile"]
+	if !okC || !okE || !okP {
+		return nil, fmt.Errorf("vuln: parse epss scores %s: expected cve,epss,percentile columns, got %v", path, header)
+	}
+
+	date := strings.SplitN(d.ScoreDate, "T", 2)[0]
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("vuln: parse epss scores %s: %w", path, err)
+		}
+		p, err1 := strconv.ParseFloat(rec[epssCol], 64)
+		pct, err2 := strconv.ParseFloat(rec[pctCol], 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("vuln: parse epss scores %s: bad row %v", path, rec)
+		}
+		d.scores[strings.ToUpper(rec[cveCol])] = EPSSScore{Probability: p, Percentile: pct, Date: date}
+	}
+	return d, nil
+}
+
+func (d *EPSSData) Len() int { return len(d.scores) }
+
+// Enrichment is the optional exploit data applied to findings. Either
+// source may be nil.
+type Enrichment struct {
+	KEV  *KEVCatalog
+	EPSS *EPSSData
+}
+
+// Enrich sets KnownExploited and EPSS on findings whose ID or any alias is
+// in the loaded data. When aliases carry different EPSS scores the highest
+// wins.
+func Enrich(findings []Finding, e Enrichment) {
+	for i := range findings {
+		f := &findings[i]
+		for _, id := range findingIDs(*f) {
+			if e.KEV != nil && f.KnownExploited == nil {
+				if k, ok := e.KEV.entries[id]; ok {
+					f.KnownExploited = &k
+				}
+			}
+			if e.EPSS != nil {
+				if s, ok := e.EPSS.scores[id]; ok && (f.EPSS == nil || s.Probability > f.EPSS.Probability) {
+					f.EPSS = &s
+				}
+			}
+		}
+	}
+}
+
+// Sort keys accepted by SortFindings.
+const (
+	SortSeverity = "severity"
+	SortCVSS     = "cvss"
+	SortEPSS     = "epss"
+	SortKEV      = "kev"
+	SortPackage  = "package"
+	SortID       = "id"
+)
+
+// SortFindings orders findings in place, most urgent first for the
+// risk keys. Ties fall back to severity, then package and ID, so output is
+// stable between runs. An empty key keeps scan order.
+func SortFindings(findings []Finding, by string) error {
+	var cmp func(a, b Finding) int
+	switch strings.ToLower(by) {
+	case "":
+		return nil
+	case SortSeverity:
+		cmp = func(a, b Finding) int {
+			return cmpDesc(float64(severityRank(a.Severity)), float64(severityRank(b.Severity)))
+		}
+	case SortCVSS:
+		cmp = func(a, b Finding) int { return cmpDesc(a.CVSSScore, b.CVSSScore) }
+	case SortEPSS:
+		cmp = func(a, b Finding) int { return cmpDesc(epssOf(a), epssOf(b)) }
+	case SortKEV:
+		cmp = func(a, b Finding) int {
+			if c := cmpDesc(kevOf(a), kevOf(b)); c != 0 {
+				return c
+			}
+			return cmpDesc(epssOf(a), epssOf(b))
+		}
+	case SortPackage:
+		cmp = func(a, b Finding) int {
+			return strings.Compare(a.PackageName+"@"+a.PackageVersion, b.PackageName+"@"+b.PackageVersion)
+		}
+	case SortID:
+		cmp = func(a, b Finding) int { return strings.Compare(a.VulnID, b.VulnID) }
+	default:
+		return fmt.Errorf("invalid sort key %q (use severity, cvss, epss, kev, package or id)", by)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if c := cmp(a, b); c != 0 {
+			return c < 0
+		}
+		if c := cmpDesc(float64(severityRank(a.Severity)), float64(severityRank(b.Severity))); c != 0 {
+			return c < 0
+		}
+		if c := strings.Compare(a.PackageName, b.PackageName); c != 0 {
+			return c < 0
+		}
+		return a.VulnID < b.VulnID
+	})
+	return nil
+}
+
+func cmpDesc(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func epssOf(f Finding) float64 {
+	if f.EPSS == nil {
+		return -1
+	}
+	return f.EPSS.Probability
+}
+
+func kevOf(f Finding) float64 {
+	if f.KnownExploited == nil {
+		return 0
+	}
+	return 1
+}
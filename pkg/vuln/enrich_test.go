@@ -0,0 +1,114 @@
+package vuln
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKEV = `{
+	"title": "CISA Catalog of Known Exploited Vulnerabilities",
+	"catalogVersion": "2024.06.01",
+	"vulnerabilities": [
+		{"cveID": "CVE-2021-44228", "dateAdded": "2021-12-10", "dueDate": "2021-12-24",
+		 "requiredAction": "Apply updates", "knownRansomwareCampaignUse": "Known"}
+	]
+}`
+
+const testEPSS = `#model_version:v2023.03.01,score_date:2024-06-01T00:00:00+0000
+cve,epss,percentile
+CVE-2021-44228,0.97565,0.99996
+CVE-2024-0001,0.00043,0.09
+CVE-2024-0002,0.20000,0.95
+`
+
+func TestEnrich(t *testing.T) {
+	dir := t.TempDir()
+	kevPath := filepath.Join(dir, "kev.json")
+	if err := os.WriteFile(kevPath, []byte(testKEV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(testEPSS))
+	zw.Close()
+	epssPath := filepath.Join(dir, "epss.csv.gz")
+	if err := os.WriteFile(epssPath, gz.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	kev, err := LoadKEV(kevPath)
+	if err != nil || kev.Len() != 1 || kev.Version != "2024.06.01" {
+		t.Fatalf("LoadKEV: %v %+v", err, kev)
+	}
+	epss, err := LoadEPSS(epssPath)
+	if err != nil || epss.Len() != 3 || epss.ModelVersion != "v2023.03.01" {
+		t.Fatalf("LoadEPSS: %v %+v", err, epss)
+	}
+
+	findings := []Finding{
+		{VulnID: "CVE-2024-0001", PackageName: "a", Severity: SeverityCritical, CVSSScore: 9.8},
+		{VulnID: "GHSA-jfh8-c2jp-5v3q", Aliases: []string{"CVE-2021-44228"}, PackageName: "log4j-core", Severity: SeverityLow},
+		{VulnID: "CVE-2024-0002", PackageName: "b", Severity: SeverityMedium},
+		{VulnID: "CVE-2024-0003", PackageName: "c", Severity: SeverityHigh},
+	}
+	Enrich(findings, Enrichment{KEV: kev, EPSS: epss})
+
+	log4j := findings[1]
+	if log4j.KnownExploited == nil || !log4j.KnownExploited.KnownRansomware || log4j.KnownExploited.DueDate != "2021-12-24" {
+		t.Fatalf("expected KEV entry via alias, got %+v", log4j.KnownExploited)
+	}
+	if log4j.EPSS == nil || log4j.EPSS.Probability != 0.97565 || log4j.EPSS.Date != "2024-06-01" {
+		t.Fatalf("unexpected EPSS: %+v", log4j.EPSS)
+	}
+	if findings[3].EPSS != nil || findings[3].KnownExploited != nil {
+		t.Fatalf("unexpected enrichment: %+v", findings[3])
+	}
+	if s := Summarize(findings); s.KnownExploited != 1 {
+		t.Fatalf("expected 1 known exploited, got %d", s.KnownExploited)
+	}
+
+	// KEV fails even though the finding is low severity
+	v := Evaluate(findings, nil, Policy{FailOn: SeverityCritical, FailOnKEV: true, FailOnEPSS: 0.5})
+	if len(v.Rules) != 3 || v.Rules[1].Rule != RuleKEV || v.Rules[1].Passed || len(v.Rules[2].Findings) != 1 {
+		t.Fatalf("unexpected verdict: %+v", v)
+	}
+
+	order := func() []string {
+		out := make([]string, len(findings))
+		for i, f := range findings {
+			out[i] = f.PackageName
+		}
+		return out
+	}
+	for by, want := range map[string]string{
+		SortKEV:      "log4j-core,b,a,c",
+		SortEPSS:     "log4j-core,b,a,c",
+		SortSeverity: "a,c,b,log4j-core",
+		SortCVSS:     "a,c,b,log4j-core",
+		SortPackage:  "a,b,c,log4j-core",
+	} {
+		if err := SortFindings(findings, by); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(order(), ","); got != want {
+			t.Errorf("sort by %s: got %s, want %s", by, got, want)
+		}
+	}
+	if err := SortFindings(findings, "age"); err == nil {
+		t.Error("expected error for unknown sort key")
+	}
+}
+
+func TestLoadEPSS_BadHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "epss.csv")
+	if err := os.WriteFile(path, []byte("id,score\nCVE-1,0.1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadEPSS(path); err == nil {
+		t.Fatal("expected error for missing columns")
+	}
+}
@@ -0,0 +1,73 @@
+package vuln
+
+import (
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/version"
+)
+
+// fixedVersions collects, from the affected entries that describe this
+// package, the "fixed" event that ends the affected interval the installed
+// version is in. Ranges that do not contain the installed version are about
+// other branches and are skipped, as are GIT ranges: their events are
+// commit hashes, not versions a developer can upgrade to.
+func fixedVersions(v osvVulnerability, p sbom.NormalizedPackage) []string {
+	var out []string
+	seen := map[string]struct{}{}
+	cmp := version.For(p.Type, p.PURL)
+	installed := strings.TrimSpace(p.Version)
+
+	for _, a := range v.Affected {
+		if !affectsPackage(a, p) {
+			continue
+		}
+		for _, r := range a.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			var fixes []string
+			if installed == "" {
+				// nothing to place in a range; every fix is a candidate
+				for _, e := range r.Events {
+					if e.Fixed != "" {
+						fixes = append(fixes, e.Fixed)
+					}
+				}
+			} else if inRange(r.Events, installed, cmp) {
+				if f := nextFix(r.Events, installed, cmp); f != "" {
+					fixes = append(fixes, f)
+				}
+			}
+			for _, f := range fixes {
+				if _, ok := seen[f]; !ok {
+					seen[f] = struct{}{}
+					out = append(out, f)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// nextFix returns the first fixed version above ver, in event order.
+func nextFix(events []osvEvent, ver string, cmp version.Comparator) string {
+	sorted := sortEvents(events, cmp)
+	for _, e := range sorted {
+		if e.Fixed != "" && cmp.Compare(e.Fixed, ver) > 0 {
+			return e.Fixed
+		}
+	}
+	return ""
+}
+
+// FilterFixable keeps only findings that can be resolved by upgrading.
+func FilterFixable(findings []Finding) []Finding {
+	out := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if f.FixAvailable {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package vuln
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+)
+
+func TestFixedVersions(t *testing.T) {
+	raw := `{
+		"id": "GHSA-h5c8-rqwp-cp95",
+		"affected": [
+			{
+				"package": {"name": "jinja2", "ecosystem": "PyPI", "purl": "pkg:pypi/jinja2"},
+				"ranges": [
+					{"type": "GIT", "repo": "https://github.com/pallets/jinja", "events": [{"introduced": "0"}, {"fixed": "7dd3680"}]},
+					{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.1.3"}]}
+				],
+				"versions": ["3.1.0", "3.1.1", "3.1.2"]
+			},
+			{
+				"package": {"name": "other", "ecosystem": "PyPI"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "9.9.9"}]}]
+			}
+		]
+	}`
+	var v osvVulnerability
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	got := fixedVersions(v, sbom.NormalizedPackage{Name: "jinja2", Version: "3.1.2", PURL: "pkg:pypi/jinja2@3.1.2"})
+	if want := []string{"3.1.3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := fixedVersions(v, sbom.NormalizedPackage{Name: "unrelated"}); len(got) != 0 {
+		t.Fatalf("expected no fixed versions, got %v", got)
+	}
+}
+
+func TestFixedVersions_SkipsOlderBranches(t *testing.T) {
+	raw := `{
+		"id": "DSA-X",
+		"affected": [{
+			"package": {"name": "openssl", "ecosystem": "Debian:12", "purl": "pkg:deb/debian/openssl"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [
+				{"introduced": "0"}, {"fixed": "1.1.1n-0+deb11u5"},
+				{"introduced": "3.0.0"}, {"fixed": "3.0.11-1~deb12u2"}
+			]}]
+		}]
+	}`
+	var v osvVulnerability
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	p := sbom.NormalizedPackage{Name: "openssl", Version: "3.0.11-1~deb12u1", Type: "deb", PURL: "pkg:deb/debian/openssl@3.0.11-1~deb12u1"}
+	if got, want := fixedVersions(v, p), []string{"3.0.11-1~deb12u2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// dpkg ordering: ~deb12u1 sorts before the plain revision
+	c := osvCoordinates{Ecosystem: "Debian:12", Name: "openssl", Version: p.Version}
+	if !affectsVersion(v, c) {
+		t.Fatal("expected 3.0.11-1~deb12u1 to be affected")
+	}
+	c.Version = "3.0.11-1"
+	if affectsVersion(v, c) {
+		t.Fatal("expected 3.0.11-1 to be fixed")
+	}
+}
+
+func TestFixedVersions_Distro(t *testing.T) {
+	// Debian records name the source package and one entry per release.
+	raw := `{
+		"id": "DEBIAN-CVE-2023-5678",
+		"affected": [
+			{
+				"package": {"name": "openssl", "ecosystem": "Debian:11", "purl": "pkg:deb/debian/openssl?arch=source"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.1.1w-0+deb11u2"}]}]
+			},
+			{
+				"package": {"name": "openssl", "ecosystem": "Debian:12", "purl": "pkg:deb/debian/openssl?arch=source"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.13-1~deb12u1"}]}]
+			}
+		]
+	}`
+	var v osvVulnerability
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	p := sbom.NormalizedPackage{
+		Name: "libssl3", Version: "3.0.11-1~deb12u2", Type: "deb",
+		PURL:     "pkg:deb/debian/libssl3@3.0.11-1~deb12u2?arch=amd64&distro=debian-12&upstream=openssl",
+		Upstream: &sbom.Upstream{Name: "openssl"},
+	}
+	if got, want := fixedVersions(v, p), []string{"3.0.13-1~deb12u1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFixedVersions_OnlyContainingRange(t *testing.T) {
+	raw := `{
+		"id": "GHSA-multi-branch",
+		"affected": [{
+			"package": {"name": "lib", "ecosystem": "PyPI", "purl": "pkg:pypi/lib"},
+			"ranges": [
+				{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.5.2"}, {"introduced": "2.0.0"}, {"fixed": "2.3.0"}]},
+				{"type": "ECOSYSTEM", "events": [{"introduced": "3.0.0"}, {"fixed": "3.1.4"}]}
+			]
+		}]
+	}`
+	var v osvVulnerability
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	for installed, want := range map[string][]string{
+		"1.4.0": {"1.5.2"},
+		"2.1.0": {"2.3.0"},
+		"3.0.1": {"3.1.4"},
+		"1.6.0": nil, // between branches: not affected, nothing to suggest
+	} {
+		p := sbom.NormalizedPackage{Name: "lib", Version: installed, Type: "python", PURL: "pkg:pypi/lib@" + installed}
+		if got := fixedVersions(v, p); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", installed, want, got)
+		}
+	}
+}
+
+func TestFilterFixable(t *testing.T) {
+	in := []Finding{
+		{VulnID: "A", FixAvailable: true, FixedVersions: []string{"1.2.3"}},
+		{VulnID: "B"},
+	}
+	out := FilterFixable(in)
+	if len(out) != 1 || out[0].VulnID != "A" {
+		t.Fatalf("unexpected result: %+v", out)
+	}
+	if got := formatFix(out[0]); got != "upgrade to 1.2.3" {
+		t.Fatalf("unexpected fix text %q", got)
+	}
+}
@@ -7,15 +7,25 @@ import (
 )
 
 type IgnoreFile struct {
-	Ignore []struct {
-		VulnID string `yaml:"vulnId"`
-		Reason string `yaml:"reason"`
-	} `yaml:"ignore"`
+	Ignore []IgnoreRule `yaml:"ignore"`
 }
 
-func LoadIgnoreFile(path string) (map[string]struct{}, error) {
+// IgnoreRule records a triage decision for a single vulnerability.
+type IgnoreRule struct {
+	VulnID string `yaml:"vulnId"`
+	Reason string `yaml:"reason"`
+
+	// Justification is an optional OpenVEX justification label
+	// (e.g. vulnerable_code_not_in_execute_path). When empty, VEX output
+	// falls back to inferring one from Reason.
+	Justification string `yaml:"justification,omitempty"`
+}
+
+// ReadIgnoreFile parses the full ignore file, keeping reasons and justifications.
+// An empty path yields an empty file.
+func ReadIgnoreFile(path string) (*IgnoreFile, error) {
 	if path == "" {
-		return make(map[string]struct{}), nil
+		return &IgnoreFile{}, nil
 	}
 
 	b, err := os.ReadFile(path)
@@ -27,6 +37,14 @@ func LoadIgnoreFile(path string) (map[string]struct{}, error) {
 	if err := yaml.Unmarshal(b, &f); err != nil {
 		return nil, err
 	}
+	return &f, nil
+}
+
+func LoadIgnoreFile(path string) (map[string]struct{}, error) {
+	f, err := ReadIgnoreFile(path)
+	if err != nil {
+		return nil, err
+	}
 
 	out := map[string]struct{}{}
 
@@ -43,10 +61,24 @@ func FilterIgnored(findings []Finding, ignored map[string]struct{}) []Finding {
 
 	out := make([]Finding, 0, len(findings))
 	for _, f := range findings {
-		if _, ok := ignored[f.VulnID]; ok {
+		if isIgnored(f, ignored) {
 			continue
 		}
 		out = append(out, f)
 	}
 	return out
 }
+
+// isIgnored matches aliases too: findings are reported under their CVE when
+// one exists, but an ignore entry may name the GHSA or distro advisory.
+func isIgnored(f Finding, ignored map[string]struct{}) bool {
+	if _, ok := ignored[f.VulnID]; ok {
+		return true
+	}
+	for _, a := range f.Aliases {
+		if _, ok := ignored[a]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package vuln
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// LocalDB is an on-disk copy of OSV records for air-gapped scanning.
+// It is fed from the ecosystem exports OSV publishes as zip files
+// (https://osv-vulnerabilities.storage.googleapis.com/<ecosystem>/all.zip).
+type LocalDB struct {
+	db   *sql.DB
+	Path string
+}
+
+const localDBSchema = `
+CREATE TABLE IF NOT EXISTS vulns (
+	id       TEXT PRIMARY KEY,
+	modified TEXT,
+	record   BLOB NOT NULL
+);
+CREATE TABLE IF NOT EXISTS affected (
+	vuln_id   TEXT NOT NULL,
+	ecosystem TEXT NOT NULL,
+	name      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS affected_pkg ON affected(name, ecosystem);
+CREATE INDEX IF NOT EXISTS affected_vuln ON affected(vuln_id);
+CREATE TABLE IF NOT EXISTS imports (
+	source      TEXT PRIMARY KEY,
+	imported_at TEXT NOT NULL,
+	records     INTEGER NOT NULL
+);
+`
+
+// DefaultLocalDBPath is where the database lives unless --db is given.
+func DefaultLocalDBPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("osv db: locate cache dir: %w", err)
+	}
+	return filepath.Join(dir, "provavalidator", "osv.db"), nil
+}
+
+// OpenLocalDB opens (and creates if needed) the database at path.
+// An empty path uses DefaultLocalDBPath.
+func OpenLocalDB(path string) (*LocalDB, error) {
+	if path == "" {
+		p, err := DefaultLocalDBPath()
+		if err != nil {
+			return nil, err
+		}
+		path = p
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("osv db: create dir: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("osv db: open %q: %w", path, err)
+	}
+	if _, err := db.Exec(localDBSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("osv db: init schema: %w", err)
+	}
+	return &LocalDB{db: db, Path: path}, nil
+}
+
+func (d *LocalDB) Close() error {
+	return d.db.Close()
+}
+
+// ImportZip loads every OSV JSON record from an ecosystem export. Records
+// already present are replaced, so re-importing a fresh export updates the
+// database in place. Records that are not valid OSV JSON are skipped and
+// counted rather than failing the whole import.
+func (d *LocalDB) ImportZip(ctx context.Context, path string) (imported, skipped int, err error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("osv db: open %q: %w", path, err)
+	}
+	defer zr.Close()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("osv db: begin import: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		b, err := readZipFile(f)
+		if err != nil {
+			return 0, 0, fmt.Errorf("osv db: read %s: %w", f.Name, err)
+		}
+		v, err := parseRecord(b)
+		if err != nil {
+			skipped++
+			continue
+		}
+		if err := storeRecord(ctx, tx, v, b); err != nil {
+			return 0, 0, fmt.Errorf("osv db: import %s: %w", f.Name, err)
+		}
+		imported++
+	}
+
+	// every OSV export is called all.zip; the directory tells them apart
+	source, err := filepath.Abs(path)
+	if err != nil {
+		source = path
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR REPLACE INTO imports(source, imported_at, records) VALUES (?, ?, ?)`,
+		source, time.Now().UTC().Format(time.RFC3339), imported,
+	); err != nil {
+		return 0, 0, fmt.Errorf("osv db: record import: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("osv db: commit import: %w", err)
+	}
+	return imported, skipped, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func parseRecord(b []byte) (osvVulnerability, error) {
+	var v osvVulnerability
+	if err := json.Unmarshal(b, &v); err != nil {
+		return v, err
+	}
+	if v.ID == "" {
+		return v, fmt.Errorf("record without id")
+	}
+	return v, nil
+}
+
+// storeRecord indexes affected packages by normalized name, so lookups do
+// not depend on how the record spells it (Jinja2, typing_extensions).
+func storeRecord(ctx context.Context, tx *sql.Tx, v osvVulnerability, b []byte) error {
+	if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO vulns(id, modified, record) VALUES (?, ?, ?)`, v.ID, v.Modified, b); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM affected WHERE vuln_id = ?`, v.ID); err != nil {
+		return err
+	}
+	for _, a := range v.Affected {
+		if a.Package.Name == "" || a.Package.Ecosystem == "" {
+			continue
+		}
+		name := normalizeName(a.Package.Ecosystem, a.Package.Name)
+		if _, err := tx.ExecContext(ctx, `INSERT INTO affected(vuln_id, ecosystem, name) VALUES (?, ?, ?)`, v.ID, a.Package.Ecosystem, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the records that mention the package, without evaluating
+// versions. The name as given is tried too, for databases imported before
+// names were normalized.
+func (d *LocalDB) lookup(ctx context.Context, c osvCoordinates) ([]osvVulnerability, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT DISTINCT v.record FROM vulns v
+		JOIN affected a ON a.vuln_id = v.id
+		WHERE a.name IN (?, ?) AND (a.ecosystem = ? OR a.ecosystem LIKE ? ESCAPE '\')`,
+		normalizeName(c.Ecosystem, c.Name), c.Name, c.Ecosystem, escapeLike(c.Ecosystem)+":%",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("osv db: lookup %s/%s: %w", c.Ecosystem, c.Name, err)
+	}
+	defer rows.Close()
+
+	var out []osvVulnerability
+	for rows.Next() {
+		var b []byte
+		if err := rows.Scan(&b); err != nil {
+			return nil, fmt.Errorf("osv db: scan: %w", err)
+		}
+		var v osvVulnerability
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("osv db: decode record: %w", err)
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// LocalDBStatus summarizes what the database holds and how fresh it is.
+type LocalDBStatus struct {
+	Path         string         `json:"path"`
+	Records      int            `json:"records"`
+	Ecosystems   map[string]int `json:"ecosystems"`
+	LastImport   *time.Time     `json:"lastImport,omitempty"`
+	AgeSeconds   int64          `json:"ageSeconds,omitempty"`   // since LastImport
+	LastModified string         `json:"lastModified,omitempty"` // newest record "modified" timestamp
+}
+
+func (d *LocalDB) Status(ctx context.Context) (*LocalDBStatus, error) {
+	s := &LocalDBStatus{Path: d.Path, Ecosystems: map[string]int{}}
+
+	if err := d.db.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(MAX(modified), '') FROM vulns`).Scan(&s.Records, &s.LastModified); err != nil {
+		return nil, fmt.Errorf("osv db: count records: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, `SELECT ecosystem, COUNT(DISTINCT vuln_id) FROM affected GROUP BY ecosystem ORDER BY ecosystem`)
+	if err != nil {
+		return nil, fmt.Errorf("osv db: count ecosystems: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var eco string
+		var n int
+		if err := rows.Scan(&eco, &n); err != nil {
+			return nil, fmt.Errorf("osv db: scan: %w", err)
+		}
+		s.Ecosystems[eco] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var last sql.NullString
+	if err := d.db.QueryRowContext(ctx, `SELECT MAX(imported_at) FROM imports`).Scan(&last); err != nil {
+		return nil, fmt.Errorf("osv db: read imports: %w", err)
+	}
+	if last.Valid {
+		if t, err := time.Parse(time.RFC3339, last.String); err == nil {
+			s.LastImport = &t
+			s.AgeSeconds = int64(time.Since(t) / time.Second)
+		}
+	}
+	return s, nil
+}
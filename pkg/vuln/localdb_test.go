@@ -0,0 +1,348 @@
+package vuln
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/version"
+)
+
+func writeOSVZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLocalDB_ImportAndOfflineScan(t *testing.T) {
+	zipPath := writeOSVZip(t, map[string]string{
+		"PYSEC-2024-1.json": `{
+			"id": "PYSEC-2024-1",
+			"modified": "2024-05-01T00:00:00Z",
+			"summary": "jinja2 sandbox escape",
+			"affected": [{
+				"package": {"name": "jinja2", "ecosystem": "PyPI"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.1.3"}]}]
+			}]
+		}`,
+		"DSA-1.json": `{
+			"id": "DSA-1",
+			"modified": "2024-06-01T00:00:00Z",
+			"affected": [{
+				"package": {"name": "openssl", "ecosystem": "Debian:12"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.13-1~deb12u1"}]}]
+			}]
+		}`,
+		"README.txt": "not a record",
+	})
+
+	db, err := OpenLocalDB(filepath.Join(t.TempDir(), "osv.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	n, _, err := db.ImportZip(ctx, zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 records imported, got %d", n)
+	}
+	// re-import replaces rather than duplicates
+	if _, _, err := db.ImportZip(ctx, zipPath); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := db.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Records != 2 || st.Ecosystems["PyPI"] != 1 || st.Ecosystems["Debian:12"] != 1 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+	if st.LastImport == nil || st.LastModified != "2024-06-01T00:00:00Z" {
+		t.Fatalf("unexpected status times: %+v", st)
+	}
+
+	pkgs := []sbom.NormalizedPackage{
+		{Name: "jinja2", Version: "3.1.2", PURL: "pkg:pypi/jinja2@3.1.2"},
+		{Name: "jinja2-fixed", Version: "3.1.4", PURL: "pkg:pypi/jinja2@3.1.4"},
+		{Name: "libssl3", Version: "3.0.11-1~deb12u2", PURL: "pkg:deb/debian/libssl3@3.0.11-1~deb12u2?distro=debian-12&upstream=openssl"},
+		{Name: "libssl3-other-release", Version: "3.0.11-1", PURL: "pkg:deb/debian/libssl3@3.0.11-1?distro=debian-11&upstream=openssl"},
+		{Name: "no-purl", Version: "1.0"},
+	}
+	res, err := ScanNormalizedPackagesOffline(ctx, db, pkgs, DefaultScanOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := res.Findings
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %+v", len(findings), findings)
+	}
+	if findings[0].VulnID != "PYSEC-2024-1" || findings[0].Summary != "jinja2 sandbox escape" || !findings[0].FixAvailable {
+		t.Fatalf("unexpected first finding: %+v", findings[0])
+	}
+	if findings[1].VulnID != "DSA-1" || findings[1].PackageName != "libssl3" {
+		t.Fatalf("unexpected second finding: %+v", findings[1])
+	}
+}
+
+func TestScanSBOM_UsesSBOMDistro(t *testing.T) {
+	zipPath := writeOSVZip(t, map[string]string{
+		"DSA-11.json": `{"id": "DSA-11", "affected": [{"package": {"name": "openssl", "ecosystem": "Debian:11"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.1.1w-0+deb11u2"}]}]}]}`,
+		"DSA-12.json": `{"id": "DSA-12", "affected": [{"package": {"name": "openssl", "ecosystem": "Debian:12"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.13-1~deb12u1"}]}]}]}`,
+	})
+	dbPath := filepath.Join(t.TempDir(), "osv.db")
+	db, err := OpenLocalDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := db.ImportZip(context.Background(), zipPath); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// the purl has no distro qualifier; the release comes from the SBOM
+	s := &sbom.ResolvedSBOM{
+		Source: sbom.SourceGenerated,
+		Packages: []sbom.NormalizedPackage{
+			{Name: "openssl", Version: "3.0.11-1~deb12u2", Type: "deb", PURL: "pkg:deb/debian/openssl@3.0.11-1~deb12u2"},
+		},
+		Distro: &sbom.Distro{ID: "debian", VersionID: "12"},
+	}
+	opts := DefaultScanOptions()
+	opts.Offline, opts.DBPath = true, dbPath
+	res, err := ScanSBOM(context.Background(), s, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Findings) != 1 || res.Findings[0].VulnID != "DSA-12" {
+		t.Fatalf("expected only DSA-12, got %+v", res.Findings)
+	}
+	if res.SBOM != s || res.Coverage.SBOMSource != sbom.SourceGenerated {
+		t.Fatalf("scan result does not record its SBOM: %+v", res.Coverage)
+	}
+}
+
+func TestLocalDB_ImportsPerExport(t *testing.T) {
+	// both exports are named all.zip, as OSV publishes them
+	pypi := writeOSVZip(t, map[string]string{
+		"PYSEC-2024-1.json": `{"id": "PYSEC-2024-1", "affected": [{"package": {"name": "jinja2", "ecosystem": "PyPI"}}]}`,
+	})
+	debian := writeOSVZip(t, map[string]string{
+		"DSA-1.json": `{"id": "DSA-1", "affected": [{"package": {"name": "openssl", "ecosystem": "Debian:12"}}]}`,
+		"DSA-2.json": `{"id": "DSA-2", "affected": [{"package": {"name": "curl", "ecosystem": "Debian:12"}}]}`,
+	})
+
+	db, err := OpenLocalDB(filepath.Join(t.TempDir(), "osv.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	for _, path := range []string{pypi, debian} {
+		if _, _, err := db.ImportZip(ctx, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.db.QueryContext(ctx, `SELECT source, records FROM imports`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	got := map[string]int{}
+	for rows.Next() {
+		var source string
+		var n int
+		if err := rows.Scan(&source, &n); err != nil {
+			t.Fatal(err)
+		}
+		got[source] = n
+	}
+	if want := map[string]int{pypi: 1, debian: 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("imports = %v, want %v", got, want)
+	}
+}
+
+func TestLocalDB_NameNormalization(t *testing.T) {
+	zipPath := writeOSVZip(t, map[string]string{
+		"PYSEC-2024-2.json": `{
+			"id": "PYSEC-2024-2",
+			"affected": [{
+				"package": {"name": "Jinja2", "ecosystem": "PyPI"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.1.3"}]}]
+			}]
+		}`,
+		"PYSEC-2024-3.json": `{
+			"id": "PYSEC-2024-3",
+			"affected": [{
+				"package": {"name": "typing-extensions", "ecosystem": "PyPI"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "4.8.0"}]}]
+			}]
+		}`,
+		"DSA-2.json": `{
+			"id": "DSA-2",
+			"affected": [{
+				"package": {"name": "openssl", "ecosystem": "Debian:12"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.13-1~deb12u1"}]}]
+			}]
+		}`,
+		"broken.json": `{"id": "BROKEN", "affected": [`,
+	})
+
+	db, err := OpenLocalDB(filepath.Join(t.TempDir(), "osv.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	n, skipped, err := db.ImportZip(ctx, zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || skipped != 1 {
+		t.Fatalf("expected 3 imported and 1 skipped, got %d and %d", n, skipped)
+	}
+
+	pkgs := []sbom.NormalizedPackage{
+		{Name: "jinja2", Version: "3.1.2", Type: "python", PURL: "pkg:pypi/jinja2@3.1.2"},
+		{Name: "typing_extensions", Version: "4.7.1", Type: "python", PURL: "pkg:pypi/typing_extensions@4.7.1"},
+		// source package known from dpkg metadata, not the PURL
+		{
+			Name: "libssl3", Version: "3.0.11-1~deb12u2", Type: "deb",
+			PURL:     "pkg:deb/debian/libssl3@3.0.11-1~deb12u2?distro=debian-12",
+			Upstream: &sbom.Upstream{Name: "openssl"},
+		},
+	}
+	res, err := ScanNormalizedPackagesOffline(ctx, db, pkgs, DefaultScanOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range res.Findings {
+		got[f.PackageName] = f.VulnID
+	}
+	want := map[string]string{"jinja2": "PYSEC-2024-2", "typing_extensions": "PYSEC-2024-3", "libssl3": "DSA-2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	for in, want := range map[string]string{
+		"Jinja2":            "jinja2",
+		"typing_extensions": "typing-extensions",
+		"zope.interface":    "zope-interface",
+		"Foo__Bar-.baz":     "foo-bar-baz",
+	} {
+		if got := normalizeName("PyPI", in); got != want {
+			t.Errorf("normalizeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := normalizeName("Debian:12", "libstdc++6"); got != "libstdc++6" {
+		t.Errorf("non-PyPI name changed: %q", got)
+	}
+}
+
+func TestCoordinatesFromPURL(t *testing.T) {
+	tests := []struct {
+		purl string
+		want osvCoordinates
+	}{
+		{"pkg:pypi/jinja2@3.1.2", osvCoordinates{"PyPI", "jinja2", "3.1.2"}},
+		{"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", osvCoordinates{"Maven", "org.apache.logging.log4j:log4j-core", "2.14.1"}},
+		{"pkg:npm/%40babel/core@7.0.0", osvCoordinates{"npm", "@babel/core", "7.0.0"}},
+		{"pkg:golang/golang.org/x/net@v0.17.0", osvCoordinates{"Go", "golang.org/x/net", "v0.17.0"}},
+		{"pkg:apk/alpine/busybox@1.36.1-r5?arch=x86_64&distro=alpine-3.19.1&upstream=busybox", osvCoordinates{"Alpine:v3.19", "busybox", "1.36.1-r5"}},
+		{"pkg:deb/debian/libc6@2.36-9?distro=debian-12.5&upstream=glibc", osvCoordinates{"Debian:12", "glibc", "2.36-9"}},
+		{"pkg:deb/ubuntu/bash@5.1-6?distro=ubuntu-22.04", osvCoordinates{"Ubuntu:22.04:LTS", "bash", "5.1-6"}},
+		{"pkg:deb/ubuntu/bash@5.2.15-2ubuntu1?distro=ubuntu-23.10", osvCoordinates{"Ubuntu:23.10", "bash", "5.2.15-2ubuntu1"}},
+	}
+	for _, tt := range tests {
+		got, err := coordinatesFromPURL(tt.purl, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.purl, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.purl, tt.want, got)
+		}
+	}
+
+	if _, err := coordinatesFromPURL("pkg:deb/unknownos/x@1", nil); err == nil {
+		t.Fatal("expected error for unknown distro")
+	}
+
+	// release comes from the image when the purl has no distro qualifier
+	got, err := coordinatesFromPURL("pkg:apk/alpine/musl@1.2.4-r2", &sbom.Distro{ID: "alpine", VersionID: "3.19.1"})
+	if err != nil || got.Ecosystem != "Alpine:v3.19" {
+		t.Fatalf("expected Alpine:v3.19, got %+v (%v)", got, err)
+	}
+}
+
+func TestCoordinatesFromType_UbuntuLTS(t *testing.T) {
+	// packages without a PURL are queried by name and ecosystem, which the
+	// OSV API matches exactly
+	p := sbom.NormalizedPackage{Name: "bash", Version: "5.1-6ubuntu1", Type: "deb"}
+	for release, want := range map[string]string{
+		"22.04": "Ubuntu:22.04:LTS",
+		"24.04": "Ubuntu:24.04:LTS",
+		"23.04": "Ubuntu:23.04",
+		"23.10": "Ubuntu:23.10",
+	} {
+		c, err := coordinatesFromType(p, &sbom.Distro{ID: "ubuntu", VersionID: release})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Ecosystem != want {
+			t.Errorf("%s: expected %s, got %s", release, want, c.Ecosystem)
+		}
+	}
+}
+
+func TestInRange(t *testing.T) {
+	events := []osvEvent{{Introduced: "1.2.0"}, {Fixed: "1.2.5"}, {Introduced: "0"}, {LastAffected: "1.0.3"}}
+	for v, want := range map[string]bool{
+		"0.9":   true,
+		"1.0.3": true,
+		"1.1.0": false,
+		"1.2.0": true,
+		"1.2.4": true,
+		"1.2.5": false,
+		"2.0":   false,
+	} {
+		if got := inRange(events, v, version.Generic); got != want {
+			t.Errorf("inRange(%s) = %v, want %v", v, got, want)
+		}
+	}
+}
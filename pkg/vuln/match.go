@@ -0,0 +1,100 @@
+package vuln
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/version"
+)
+
+// affectsVersion evaluates an OSV record against a package locally, the way
+// the OSV API would: an explicit versions list match, or membership in any
+// SEMVER/ECOSYSTEM range. GIT ranges need commit history and are skipped.
+// Spec: https://ossf.github.io/osv-schema/#evaluation
+func affectsVersion(v osvVulnerability, c osvCoordinates) bool {
+	cmp := version.ForEcosystem(c.Ecosystem)
+	name := normalizeName(c.Ecosystem, c.Name)
+	for _, a := range v.Affected {
+		if normalizeName(c.Ecosystem, a.Package.Name) != name || !ecosystemMatches(a.Package.Ecosystem, c.Ecosystem) {
+			continue
+		}
+		for _, ver := range a.Versions {
+			if ver == c.Version {
+				return true
+			}
+		}
+		for _, r := range a.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			if inRange(r.Events, c.Version, cmp) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ecosystemMatches compares a record's ecosystem to the package's. A package
+// without a release ("Debian") matches every release, and a release matches
+// its qualified spellings ("Ubuntu:22.04" matches "Ubuntu:22.04:LTS").
+func ecosystemMatches(record, pkg string) bool {
+	return record == pkg || strings.HasPrefix(record, pkg+":")
+}
+
+func inRange(events []osvEvent, ver string, cmp version.Comparator) bool {
+	affected := false
+	for _, e := range sortEvents(events, cmp) {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || cmp.Compare(ver, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if cmp.Compare(ver, e.Fixed) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if cmp.Compare(ver, e.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// sortEvents orders a copy of events by version, as evaluation requires.
+func sortEvents(events []osvEvent, cmp version.Comparator) []osvEvent {
+	sorted := make([]osvEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareEventVersions(eventVersion(sorted[i]), eventVersion(sorted[j]), cmp) < 0
+	})
+	return sorted
+}
+
+func eventVersion(e osvEvent) string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	default:
+		return e.Limit
+	}
+}
+
+// "0" means "since the beginning" and sorts before everything.
+func compareEventVersions(a, b string, cmp version.Comparator) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "0":
+		return -1
+	case b == "0":
+		return 1
+	}
+	return cmp.Compare(a, b)
+}
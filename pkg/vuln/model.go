@@ -23,4 +23,27 @@ type Finding struct {
 	// Best effort: OSV severity can be CVSSv2/v3/v4 etc; we normalize to a single float.
 	CVSSScore float64  `json:"cvssScore,omitempty"`
 	Severity  Severity `json:"severity,omitempty"`
+
+	// The vector the score was computed from, kept so reviewers can check it.
+	CVSSVector  string `json:"cvssVector,omitempty"`
+	CVSSVersion string `json:"cvssVersion,omitempty"`
+
+	// SeveritySource says which data Severity was derived from (cvss, ecosystem_specific, database_specific).
+	SeveritySource SeveritySource `json:"severitySource,omitempty"`
+
+	// FixedVersions are the versions in the package's ecosystem that fix this vulnerability.
+	FixedVersions []string `json:"fixedVersions,omitempty"`
+	FixAvailable  bool     `json:"fixAvailable"`
+
+	// Locations are the paths in the image where the package was found.
+	Locations []string `json:"locations,omitempty"`
+
+	// IntroducedVia lists dependency chains that pull the package in, root
+	// first, e.g. "app 1.0 > express 4.18.2". Empty for top-level packages
+	// and SBOMs without relationships.
+	IntroducedVia []string `json:"introducedVia,omitempty"`
+
+	// Exploit data, set by Enrich when KEV or EPSS files are given.
+	KnownExploited *KEVEntry  `json:"knownExploited,omitempty"`
+	EPSS           *EPSSScore `json:"epss,omitempty"`
 }
@@ -4,10 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 type OSVClient struct {
@@ -16,6 +24,34 @@ type OSVClient struct {
 
 	// MaxQueriesPerBatch lets you chunk big SBOMs safely
 	MaxQueriesPerBatch int
+
+	// MaxConcurrentFetches bounds parallel /v1/vulns/{id} requests during hydration
+	MaxConcurrentFetches int
+
+	// MaxPages caps how many next_page_token rounds we follow for a batch
+	MaxPages int
+
+	// MaxConcurrentBatches bounds parallel querybatch requests
+	MaxConcurrentBatches int
+
+	// MaxRetries is how many times a request is retried after a network
+	// error, 429 or 5xx. Zero disables retries.
+	MaxRetries int
+
+	// RetryBaseDelay is the first backoff; it doubles per attempt up to
+	// RetryMaxDelay, which also caps a server's Retry-After.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	// RequestsPerSecond rate-limits all requests from this client. Zero
+	// means unlimited.
+	RequestsPerSecond float64
+
+	// Cache, when set, answers repeated queries and record fetches from disk.
+	Cache *Cache
+
+	limiterOnce sync.Once
+	limiter     *rate.Limiter
 }
 
 func NewOSVClient() *OSVClient {
@@ -24,15 +60,18 @@ func NewOSVClient() *OSVClient {
 		HTTPClient: &http.Client{
 			Timeout: 20 * time.Second,
 		},
-		MaxQueriesPerBatch: 200,
+		MaxQueriesPerBatch:   200,
+		MaxConcurrentFetches: 8,
+		MaxPages:             50,
+		MaxConcurrentBatches: 4,
+		MaxRetries:           4,
+		RetryBaseDelay:       500 * time.Millisecond,
+		RetryMaxDelay:        30 * time.Second,
+		RequestsPerSecond:    20,
 	}
 }
 
-func (c *OSVClient) QueryBatch(ctx context.Context, queries []osvQuery) ([]osvQueryResult, error) {
-	if len(queries) == 0 {
-		return nil, nil
-	}
-
+func (c *OSVClient) defaults() {
 	if c.HTTPClient == nil {
 		c.HTTPClient = http.DefaultClient
 	}
@@ -44,52 +83,405 @@ func (c *OSVClient) QueryBatch(ctx context.Context, queries []osvQuery) ([]osvQu
 	if c.MaxQueriesPerBatch <= 0 {
 		c.MaxQueriesPerBatch = 200
 	}
-	all := make([]osvQueryResult, 0, len(queries))
 
-	for start := 0; start < len(queries); start += c.MaxQueriesPerBatch {
-		end := start + c.MaxQueriesPerBatch
+	if c.MaxConcurrentFetches <= 0 {
+		c.MaxConcurrentFetches = 8
+	}
 
-		if end > len(queries) {
-			end = len(queries)
+	if c.MaxPages <= 0 {
+		c.MaxPages = 50
+	}
+
+	if c.MaxConcurrentBatches <= 0 {
+		c.MaxConcurrentBatches = 1
+	}
+
+	if c.RetryBaseDelay <= 0 {
+		c.RetryBaseDelay = 500 * time.Millisecond
+	}
+
+	if c.RetryMaxDelay <= 0 {
+		c.RetryMaxDelay = 30 * time.Second
+	}
+}
+
+func (c *OSVClient) QueryBatch(ctx context.Context, queries []osvQuery) ([]osvQueryResult, error) {
+	if len(queries) == 0 {
+		return nil, nil
+	}
+	c.defaults()
+
+	if c.Cache == nil {
+		return c.queryAllPages(ctx, queries)
+	}
+
+	results := make([]osvQueryResult, len(queries))
+	var missing []int
+	for i, q := range queries {
+		if !c.Cache.get(cacheQueries, queryCacheKey(q), &results[i]) {
+			missing = append(missing, i)
 		}
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	pending := make([]osvQuery, len(missing))
+	for j, i := range missing {
+		pending[j] = queries[i]
+	}
+	fetched, err := c.queryAllPages(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range missing {
+		results[i] = fetched[j]
+		_ = c.Cache.put(cacheQueries, queryCacheKey(queries[i]), fetched[j])
+	}
+	return results, nil
+}
+
+// queryAllPages posts queries and follows pagination until every result
+// is complete.
+func (c *OSVClient) queryAllPages(ctx context.Context, queries []osvQuery) ([]osvQueryResult, error) {
+	all, err := c.postChunks(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
 
-		reqBody := osvQueryBatchRequest{
-			Queries: queries[start:end],
+	// Queries with many vulns are paginated: re-send just those queries
+	// with their page token until no result carries a token.
+	for page := 0; ; page++ {
+		var pending []int
+		for i := range all {
+			if all[i].NextPageToken != "" {
+				pending = append(pending, i)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		if page >= c.MaxPages {
+			return nil, fmt.Errorf("osv: gave up after %d pages of results", c.MaxPages)
 		}
 
-		b, err := json.Marshal(reqBody)
-		if err != nil {
-			return nil, fmt.Errorf("osv: marshal querybatch: %w", err)
+		next := make([]osvQuery, len(pending))
+		for j, i := range pending {
+			next[j] = queries[i]
+			next[j].PageToken = all[i].NextPageToken
 		}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURl+"/v1/querybatch", bytes.NewReader(b))
+		results, err := c.postChunks(ctx, next)
 		if err != nil {
-			return nil, fmt.Errorf("osv: build request: %w", err)
+			return nil, err
+		}
+		for j, i := range pending {
+			all[i].Vulns = append(all[i].Vulns, results[j].Vulns...)
+			all[i].NextPageToken = results[j].NextPageToken
 		}
+	}
 
-		req.Header.Set("Content-Type", "application/json")
+	return all, nil
+}
 
-		resp, err := c.HTTPClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("osv: http request: %w", err)
+// postChunks splits queries into batches, posts up to MaxConcurrentBatches
+// of them at once and returns the results in query order. The first error
+// cancels the batches still in flight.
+func (c *OSVClient) postChunks(ctx context.Context, queries []osvQuery) ([]osvQueryResult, error) {
+	type chunk struct{ start, end int }
+	var chunks []chunk
+	for start := 0; start < len(queries); start += c.MaxQueriesPerBatch {
+		chunks = append(chunks, chunk{start, min(start+c.MaxQueriesPerBatch, len(queries))})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		all      = make([]osvQueryResult, len(queries))
+		sem      = make(chan struct{}, c.MaxConcurrentBatches)
+	)
+
+	for _, ch := range chunks {
+		wg.Add(1)
+		go func(ch chunk) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			results, err := c.postBatch(ctx, queries[ch.start:ch.end])
+			// OSV returns results aligned with queries order.
+			if err == nil && len(results) != ch.end-ch.start {
+				err = fmt.Errorf("osv: result count mismatch: got %d, want %d", len(results), ch.end-ch.start)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			copy(all[ch.start:ch.end], results)
+		}(ch)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("osv: %w", err)
+	}
+	return all, nil
+}
+
+func (c *OSVClient) postBatch(ctx context.Context, queries []osvQuery) ([]osvQueryResult, error) {
+	reqBody := osvQueryBatchRequest{
+		Queries: queries,
+	}
+
+	b, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("osv: marshal querybatch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURl+"/v1/querybatch", bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("osv: build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var out osvQueryBatchResponse
+
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("osv: decode response: %w", err)
+	}
+	return out.Results, nil
+}
+
+// GetVuln fetches the full record for one vulnerability ID.
+func (c *OSVClient) GetVuln(ctx context.Context, id string) (*osvVulnerability, error) {
+	c.defaults()
+
+	var cached osvVulnerability
+	if c.Cache != nil && c.Cache.get(cacheVulns, id, &cached) {
+		return &cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURl+"/v1/vulns/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("osv: build request: %w", err)
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("osv: get %s: %w", id, err)
+	}
+
+	var v osvVulnerability
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, fmt.Errorf("osv: decode vuln %s: %w", id, err)
+	}
+	if c.Cache != nil {
+		_ = c.Cache.put(cacheVulns, id, v)
+	}
+	return &v, nil
+}
+
+// HydrateVulns fetches full records for the given IDs, each ID once, with at
+// most MaxConcurrentFetches requests in flight. The first error wins.
+func (c *OSVClient) HydrateVulns(ctx context.Context, ids []string) (map[string]osvVulnerability, error) {
+	c.defaults()
+
+	unique := make([]string, 0, len(ids))
+	seen := map[string]struct{}{}
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == "" {
+			continue
 		}
-		defer resp.Body.Close()
-		body, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return nil, fmt.Errorf("osv: http %d: %s", resp.StatusCode, string(body))
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		out      = make(map[string]osvVulnerability, len(unique))
+		sem      = make(chan struct{}, c.MaxConcurrentFetches)
+	)
+
+	for _, id := range unique {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			v, err := c.GetVuln(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			out[id] = *v
+		}(id)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// do sends req, retrying network errors, 429s and 5xx responses with
+// exponential backoff (or the server's Retry-After), and returns the body
+// of the first successful response.
+func (c *OSVClient) do(req *http.Request) ([]byte, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx); err != nil {
+			return nil, fmt.Errorf("osv: rate limit: %w", err)
+		}
+
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("osv: rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		body, retryAfter, err := c.doOnce(req)
+		if err == nil {
+			return body, nil
+		}
+		var re *retryableError
+		if !errors.As(err, &re) || attempt >= c.MaxRetries || ctx.Err() != nil {
+			if attempt > 0 {
+				return nil, fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return nil, err
 		}
-		var out osvQueryBatchResponse
 
-		if err := json.Unmarshal(body, &out); err != nil {
-			return nil, fmt.Errorf("osv: decode response: %w", err)
+		delay := c.backoff(attempt)
+		if retryAfter > 0 {
+			delay = min(retryAfter, c.RetryMaxDelay)
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, fmt.Errorf("osv: %w", ctx.Err())
+		case <-t.C:
 		}
-		all = append(all, out.Results...)
 	}
+}
+
+// retryableError marks failures worth another attempt.
+type retryableError struct{ err error }
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
 
-	// OSV returns results alligned with queries order.
-	if len(all) != len(queries) {
-		return nil, fmt.Errorf("osv: result count mismatch: got %d, want %d", len(all), len(queries))
+func (c *OSVClient) doOnce(req *http.Request) ([]byte, time.Duration, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("osv: http request: %w", err)
+		if req.Context().Err() != nil {
+			return nil, 0, err
+		}
+		return nil, 0, &retryableError{err}
 	}
+	defer resp.Body.Close()
 
-	return all, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, &retryableError{fmt.Errorf("osv: read response: %w", err)}
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, 0, nil
+	}
+
+	err = fmt.Errorf("osv: http %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), &retryableError{err}
+	}
+	return nil, 0, err
+}
+
+// backoff is RetryBaseDelay doubled per attempt, capped at RetryMaxDelay,
+// with jitter over the upper half so parallel callers spread out.
+func (c *OSVClient) backoff(attempt int) time.Duration {
+	d := c.RetryBaseDelay << attempt
+	if d <= 0 || d > c.RetryMaxDelay {
+		d = c.RetryMaxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// parseRetryAfter reads either form of Retry-After: delay seconds or an
+// HTTP date. Unparseable or past values mean "no hint".
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// wait blocks until the rate limiter allows another request.
+func (c *OSVClient) wait(ctx context.Context) error {
+	c.limiterOnce.Do(func() {
+		if c.RequestsPerSecond > 0 {
+			c.limiter = rate.NewLimiter(rate.Limit(c.RequestsPerSecond), 1)
+		}
+	})
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(ctx)
 }
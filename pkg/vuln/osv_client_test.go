@@ -0,0 +1,276 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueryBatch_FollowsNextPageToken(t *testing.T) {
+	var calls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req osvQueryBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		resp := osvQueryBatchResponse{Results: make([]osvQueryResult, len(req.Queries))}
+		for i, q := range req.Queries {
+			switch {
+			// first query has two pages
+			case q.Package.PURL == "pkg:npm/lodash@4.17.0" && q.PageToken == "":
+				resp.Results[i] = osvQueryResult{Vulns: []osvVulnerability{{ID: "A"}}, NextPageToken: "page-2"}
+			case q.Package.PURL == "pkg:npm/lodash@4.17.0" && q.PageToken == "page-2":
+				resp.Results[i] = osvQueryResult{Vulns: []osvVulnerability{{ID: "B"}}}
+			case q.PageToken != "":
+				t.Errorf("unexpected page token for %s", q.Package.PURL)
+			default:
+				resp.Results[i] = osvQueryResult{Vulns: []osvVulnerability{{ID: "C"}}}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	client := NewOSVClient()
+	client.BaseURl = srv.URL
+
+	results, err := client.QueryBatch(context.Background(), []osvQuery{
+		{Package: &osvPackage{PURL: "pkg:npm/lodash@4.17.0"}},
+		{Package: &osvPackage{PURL: "pkg:npm/express@4.0.0"}},
+	})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected 2 batch calls, got %d", calls)
+	}
+	if len(results[0].Vulns) != 2 || results[0].Vulns[1].ID != "B" {
+		t.Fatalf("expected both pages merged for first query, got %+v", results[0].Vulns)
+	}
+	if len(results[1].Vulns) != 1 || results[1].Vulns[0].ID != "C" {
+		t.Fatalf("unexpected second result %+v", results[1].Vulns)
+	}
+}
+
+func TestHydrateVulns_DedupesAndReportsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v1/vulns/")
+		if id == "MISSING" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(osvVulnerability{ID: id, Summary: "summary of " + id})
+	}))
+	defer srv.Close()
+
+	client := NewOSVClient()
+	client.BaseURl = srv.URL
+	client.MaxConcurrentFetches = 2
+
+	got, err := client.HydrateVulns(context.Background(), []string{"A", "B", "A", "C", "B"})
+	if err != nil {
+		t.Fatalf("hydrate failed: %v", err)
+	}
+	if len(got) != 3 || got["C"].Summary != "summary of C" {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+
+	if _, err := client.HydrateVulns(context.Background(), []string{"A", "MISSING"}); err == nil {
+		t.Fatal("expected error for missing record")
+	}
+}
+
+// flakyServer fails the first n requests with status, then answers
+// querybatch with one vuln per query named after the query's purl.
+func flakyServer(t *testing.T, n int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error":"injected"}`))
+			return
+		}
+		var req osvQueryBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := osvQueryBatchResponse{Results: make([]osvQueryResult, len(req.Queries))}
+		for i, q := range req.Queries {
+			resp.Results[i] = osvQueryResult{Vulns: []osvVulnerability{{ID: q.Package.PURL}}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func testClient(url string) *OSVClient {
+	return &OSVClient{
+		BaseURl:        url,
+		MaxRetries:     3,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  10 * time.Millisecond,
+	}
+}
+
+func purlQueries(n int) []osvQuery {
+	qs := make([]osvQuery, n)
+	for i := range qs {
+		qs[i] = osvQuery{Package: &osvPackage{PURL: fmt.Sprintf("pkg:npm/p%d@1.0.0", i)}}
+	}
+	return qs
+}
+
+func TestQueryBatch_Retries(t *testing.T) {
+	tests := []struct {
+		name       string
+		fail       int32
+		status     int
+		retryAfter string
+		wantErr    bool
+		wantCalls  int32
+	}{
+		{name: "503 then ok", fail: 2, status: http.StatusServiceUnavailable, wantCalls: 3},
+		{name: "429 with retry-after", fail: 1, status: http.StatusTooManyRequests, retryAfter: "0", wantCalls: 2},
+		{name: "gives up", fail: 100, status: http.StatusBadGateway, wantErr: true, wantCalls: 4},
+		{name: "4xx not retried", fail: 100, status: http.StatusBadRequest, wantErr: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, tt.fail, tt.status, tt.retryAfter)
+
+			res, err := testClient(srv.URL).QueryBatch(context.Background(), purlQueries(2))
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (len(res) != 2 || res[1].Vulns[0].ID != "pkg:npm/p1@1.0.0") {
+				t.Fatalf("unexpected results: %+v", res)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestQueryBatch_RetryAfterCappedAndCancelled(t *testing.T) {
+	srv, _ := flakyServer(t, 100, http.StatusTooManyRequests, "3600")
+
+	c := testClient(srv.URL)
+	c.RetryMaxDelay = time.Hour // honour the server's hour-long hint...
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.QueryBatch(ctx, purlQueries(1))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	// ...but stop waiting as soon as the context ends
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("backoff ignored context: took %s", elapsed)
+	}
+}
+
+func TestQueryBatch_ParallelPreservesOrder(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		var req osvQueryBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// earlier batches answer last
+		var first int
+		fmt.Sscanf(req.Queries[0].Package.PURL, "pkg:npm/p%d@", &first)
+		time.Sleep(time.Duration(20-first) * 2 * time.Millisecond)
+
+		resp := osvQueryBatchResponse{Results: make([]osvQueryResult, len(req.Queries))}
+		for i, q := range req.Queries {
+			resp.Results[i] = osvQueryResult{Vulns: []osvVulnerability{{ID: q.Package.PURL}}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	c := testClient(srv.URL)
+	c.MaxQueriesPerBatch = 2
+	c.MaxConcurrentBatches = 4
+
+	res, err := c.QueryBatch(context.Background(), purlQueries(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range res {
+		if want := fmt.Sprintf("pkg:npm/p%d@1.0.0", i); r.Vulns[0].ID != want {
+			t.Fatalf("result %d out of order: got %s", i, r.Vulns[0].ID)
+		}
+	}
+	if p := peak.Load(); p < 2 || p > 4 {
+		t.Fatalf("expected 2-4 batches in flight, peak was %d", p)
+	}
+}
+
+func TestQueryBatch_RateLimited(t *testing.T) {
+	srv, calls := flakyServer(t, 0, 0, "")
+
+	c := testClient(srv.URL)
+	c.MaxQueriesPerBatch = 1
+	c.MaxConcurrentBatches = 5
+	c.RequestsPerSecond = 50 // one request every 20ms
+
+	start := time.Now()
+	if _, err := c.QueryBatch(context.Background(), purlQueries(6)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("6 requests at 50/s took only %s", elapsed)
+	}
+	if calls.Load() != 6 {
+		t.Fatalf("expected 6 calls, got %d", calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Wed, 01 Jan 2025 00:00:30 GMT": 30 * time.Second,
+		"Tue, 31 Dec 2024 23:59:00 GMT": 0,
+	}
+	for in, want := range tests {
+		if got := parseRetryAfter(in, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
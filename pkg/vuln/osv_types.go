@@ -9,6 +9,9 @@ type osvQuery struct {
 	Package *osvPackage `json:"package"`
 	Version string      `json:"version,omitempty"`
 	Commit  string      `json:"commit,omitempty"`
+
+	// PageToken continues a query whose previous result had next_page_token set.
+	PageToken string `json:"page_token,omitempty"`
 }
 
 type osvPackage struct {
@@ -22,18 +25,50 @@ type osvQueryBatchResponse struct {
 }
 
 type osvQueryResult struct {
-	Vulns []osvVulnerability `json:"vulns"`
+	Vulns         []osvVulnerability `json:"vulns"`
+	NextPageToken string             `json:"next_page_token,omitempty"`
 }
 
+// querybatch only fills ID and Modified; the rest comes from /v1/vulns/{id}.
 type osvVulnerability struct {
 	ID       string             `json:"id"`
+	Modified string             `json:"modified,omitempty"`
 	Summary  string             `json:"summary,omitempty"`
 	Details  string             `json:"details,omitempty"`
 	Aliases  []string           `json:"aliases,omitempty"`
 	Severity []osvSeverityEntry `json:"severity,omitempty"`
+	Affected []osvAffected      `json:"affected,omitempty"`
+
+	// Free-form per database; GHSA puts {"severity": "HIGH"} here.
+	DatabaseSpecific map[string]any `json:"database_specific,omitempty"`
+}
+
+type osvAffected struct {
+	Package  osvPackage `json:"package"`
+	Ranges   []osvRange `json:"ranges,omitempty"`
+	Versions []string   `json:"versions,omitempty"` // explicitly enumerated affected versions
+
+	// Free-form per ecosystem; distros put their rating here
+	// (e.g. Debian {"urgency": "low"}, Ubuntu {"severity": "medium"}).
+	EcosystemSpecific map[string]any `json:"ecosystem_specific,omitempty"`
+	DatabaseSpecific  map[string]any `json:"database_specific,omitempty"`
 }
 
 type osvSeverityEntry struct {
 	Type  string `json:"type"`  // e.g., "CVSS_V3"
 	Score string `json:"score"` // e.g., "7.5" or "CVSS:3.1/AV:N/..."
 }
+
+type osvRange struct {
+	Type   string     `json:"type"` // SEMVER, ECOSYSTEM or GIT
+	Repo   string     `json:"repo,omitempty"`
+	Events []osvEvent `json:"events"`
+}
+
+// osvEvent has exactly one field set.
+type osvEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
@@ -0,0 +1,268 @@
+package vuln
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Policy rule names, as reported in a Verdict.
+const (
+	RuleFailOn      = "fail-on"
+	RuleFailOnScore = "fail-on-score"
+	RuleMaxCount    = "max-" // followed by the severity, e.g. max-high
+	RuleMinCoverage = "min-coverage"
+	RuleKEV         = "kev"
+	RuleEPSS        = "epss"
+)
+
+// Policy decides whether a scan passes. Zero values disable a rule, so the
+// zero Policy passes everything.
+type Policy struct {
+	// FailOn fails on any finding at or above this severity.
+	FailOn Severity
+
+	// FailOnScore fails on any finding with a CVSS base score at or above it.
+	FailOnScore float64
+
+	// MaxCounts caps the number of findings per severity (exact match, so
+	// MaxCounts[SeverityHigh] = 5 allows five highs and any number of criticals
+	// unless those are capped too).
+	MaxCounts map[Severity]int
+
+	// FailOnKEV fails on any finding in the CISA KEV catalog, whatever its
+	// severity. FailOnEPSS fails on any finding with an EPSS probability at
+	// or above it. Both need Enrich to have run.
+	FailOnKEV  bool
+	FailOnEPSS float64
+
+	// MinCoverage fails when fewer than this percentage of packages were scanned.
+	MinCoverage float64
+
+	// OnlyFixable counts only findings with a fix available toward the
+	// finding rules.
+	OnlyFixable bool
+
+	// Baseline, when set, limits the finding rules to findings that are not
+	// in it, so existing debt does not fail the build.
+	Baseline []Finding
+}
+
+// FindingRef identifies the finding a rule tripped on.
+type FindingRef struct {
+	VulnID         string   `json:"vulnId"`
+	PackageName    string   `json:"packageName"`
+	PackageVersion string   `json:"packageVersion,omitempty"`
+	Severity       Severity `json:"severity,omitempty"`
+}
+
+func refOf(f Finding) FindingRef {
+	return FindingRef{VulnID: f.VulnID, PackageName: f.PackageName, PackageVersion: f.PackageVersion, Severity: f.Severity}
+}
+
+// Matches reports whether f is the finding r refers to.
+func (r FindingRef) Matches(f Finding) bool {
+	return r.VulnID == f.VulnID && r.PackageName == f.PackageName && r.PackageVersion == f.PackageVersion
+}
+
+// RuleResult is the outcome of one configured policy rule.
+type RuleResult struct {
+	Rule     string       `json:"rule"`
+	Passed   bool         `json:"passed"`
+	Message  string       `json:"message"`
+	Findings []FindingRef `json:"findings,omitempty"`
+}
+
+// Verdict is the outcome of evaluating a Policy. It is computed separately
+// from rendering so every output format reports the same result.
+type Verdict struct {
+	Passed bool         `json:"passed"`
+	Rules  []RuleResult `json:"rules,omitempty"`
+}
+
+// Violations are the rules that failed.
+func (v Verdict) Violations() []RuleResult {
+	var out []RuleResult
+	for _, r := range v.Rules {
+		if !r.Passed {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Violates reports whether f tripped any failed rule.
+func (v Verdict) Violates(f Finding) bool {
+	for _, r := range v.Violations() {
+		for _, ref := range r.Findings {
+			if ref.Matches(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Err is nil when the verdict passed; otherwise it describes the violations.
+func (v Verdict) Err() error {
+	violations := v.Violations()
+	switch len(violations) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New(violations[0].Message)
+	default:
+		msgs := make([]string, len(violations))
+		for i, r := range violations {
+			msgs[i] = r.Message
+		}
+		return fmt.Errorf("policy violation: %s", strings.Join(msgs, "; "))
+	}
+}
+
+// Evaluate applies p to the findings and coverage of a scan. cov may be nil
+// when coverage is not known, in which case MinCoverage is not checked.
+func Evaluate(findings []Finding, cov *Coverage, p Policy) Verdict {
+	counted := findings
+	if p.OnlyFixable {
+		counted = FilterFixable(counted)
+	}
+	if p.Baseline != nil {
+		counted = FilterNew(counted, p.Baseline)
+	}
+	scope := policyScope(p)
+
+	var rules []RuleResult
+
+	if p.FailOn != "" {
+		r := findingRule(RuleFailOn, FilterBySeverity(counted, p.FailOn))
+		if r.Passed {
+			r.Message = fmt.Sprintf("no vulnerabilities at or above severity %s%s", p.FailOn, scope)
+		} else {
+			r.Message = fmt.Sprintf("vulnerability policy violation: %d vulnerabilities found at or above severity %s%s", len(r.Findings), p.FailOn, scope)
+		}
+		rules = append(rules, r)
+	}
+
+	if p.FailOnScore > 0 {
+		var hits []Finding
+		for _, f := range counted {
+			if f.CVSSScore >= p.FailOnScore {
+				hits = append(hits, f)
+			}
+		}
+		r := findingRule(RuleFailOnScore, hits)
+		if r.Passed {
+			r.Message = fmt.Sprintf("no vulnerabilities with CVSS score >= %.1f%s", p.FailOnScore, scope)
+		} else {
+			r.Message = fmt.Sprintf("vulnerability policy violation: %d vulnerabilities with CVSS score >= %.1f%s", len(hits), p.FailOnScore, scope)
+		}
+		rules = append(rules, r)
+	}
+
+	for _, sev := range sortedSeverities(p.MaxCounts) {
+		limit := p.MaxCounts[sev]
+		var hits []Finding
+		for _, f := range counted {
+			if f.Severity == sev {
+				hits = append(hits, f)
+			}
+		}
+		r := RuleResult{Rule: RuleMaxCount + string(sev), Passed: len(hits) <= limit}
+		if r.Passed {
+			r.Message = fmt.Sprintf("%d %s vulnerabilities, maximum is %d%s", len(hits), sev, limit, scope)
+		} else {
+			r.Message = fmt.Sprintf("vulnerability policy violation: %d %s vulnerabilities, maximum is %d%s", len(hits), sev, limit, scope)
+			for _, f := range hits {
+				r.Findings = append(r.Findings, refOf(f))
+			}
+		}
+		rules = append(rules, r)
+	}
+
+	if p.FailOnKEV {
+		var hits []Finding
+		for _, f := range counted {
+			if f.KnownExploited != nil {
+				hits = append(hits, f)
+			}
+		}
+		r := findingRule(RuleKEV, hits)
+		if r.Passed {
+			r.Message = "no known exploited vulnerabilities" + scope
+		} else {
+			r.Message = fmt.Sprintf("vulnerability policy violation: %d known exploited vulnerabilities (CISA KEV)%s", len(hits), scope)
+		}
+		rules = append(rules, r)
+	}
+
+	if p.FailOnEPSS > 0 {
+		var hits []Finding
+		for _, f := range counted {
+			if f.EPSS != nil && f.EPSS.Probability >= p.FailOnEPSS {
+				hits = append(hits, f)
+			}
+		}
+		r := findingRule(RuleEPSS, hits)
+		if r.Passed {
+			r.Message = fmt.Sprintf("no vulnerabilities with EPSS probability >= %.2f%s", p.FailOnEPSS, scope)
+		} else {
+			r.Message = fmt.Sprintf("vulnerability policy violation: %d vulnerabilities with EPSS probability >= %.2f%s", len(hits), p.FailOnEPSS, scope)
+		}
+		rules = append(rules, r)
+	}
+
+	if cov != nil && p.MinCoverage > 0 {
+		r := RuleResult{Rule: RuleMinCoverage, Passed: true,
+			Message: fmt.Sprintf("%.1f%% of packages scanned, minimum is %.1f%%", cov.Percent, p.MinCoverage)}
+		if err := EnforceCoverage(*cov, p.MinCoverage); err != nil {
+			r.Passed = false
+			r.Message = err.Error()
+		}
+		rules = append(rules, r)
+	}
+
+	v := Verdict{Passed: true, Rules: rules}
+	for _, r := range rules {
+		v.Passed = v.Passed && r.Passed
+	}
+	return v
+}
+
+// EnforcePolicy returns the policy violations for findings as an error.
+func EnforcePolicy(findings []Finding, p Policy) error {
+	return Evaluate(findings, nil, p).Err()
+}
+
+func findingRule(name string, hits []Finding) RuleResult {
+	r := RuleResult{Rule: name, Passed: len(hits) == 0}
+	for _, f := range hits {
+		r.Findings = append(r.Findings, refOf(f))
+	}
+	return r
+}
+
+func policyScope(p Policy) string {
+	var parts []string
+	if p.OnlyFixable {
+		parts = append(parts, "fixable")
+	}
+	if p.Baseline != nil {
+		parts = append(parts, "new")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (counting only " + strings.Join(parts, ", ") + " findings)"
+}
+
+// sortedSeverities orders count limits from most to least severe.
+func sortedSeverities(m map[Severity]int) []Severity {
+	out := make([]Severity, 0, len(m))
+	for s := range m {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return severityRank(out[i]) > severityRank(out[j]) })
+	return out
+}
@@ -0,0 +1,129 @@
+package vuln
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func policyFindings() []Finding {
+	return []Finding{
+		{VulnID: "CVE-1", PackageName: "openssl", PackageType: "deb", PackageVersion: "3.0.11", Severity: SeverityCritical, CVSSScore: 9.8},
+		{VulnID: "CVE-2", PackageName: "zlib", PackageType: "deb", PackageVersion: "1.2.13", Severity: SeverityHigh, CVSSScore: 7.5, FixAvailable: true, FixedVersions: []string{"1.3"}},
+		{VulnID: "CVE-3", PackageName: "curl", PackageType: "deb", PackageVersion: "8.0.0", Severity: SeverityHigh, CVSSScore: 8.1},
+		{VulnID: "CVE-4", PackageName: "bash", PackageType: "deb", PackageVersion: "5.2", Severity: SeverityLow},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	findings := policyFindings()
+	cov := &Coverage{Packages: 10, Queryable: 8, Percent: 80}
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		wantPassed bool
+		wantRules  map[string]int // rule -> number of findings it tripped on
+	}{
+		{name: "empty policy passes", wantPassed: true, wantRules: map[string]int{}},
+		{
+			name:      "fail-on high",
+			policy:    Policy{FailOn: SeverityHigh},
+			wantRules: map[string]int{RuleFailOn: 3},
+		},
+		{
+			name:      "score threshold",
+			policy:    Policy{FailOnScore: 8.0},
+			wantRules: map[string]int{RuleFailOnScore: 2},
+		},
+		{
+			name:      "count limits",
+			policy:    Policy{MaxCounts: map[Severity]int{SeverityHigh: 1, SeverityLow: 1}},
+			wantRules: map[string]int{"max-high": 2, "max-low": 0},
+		},
+		{
+			name:       "only fixable",
+			policy:     Policy{FailOn: SeverityCritical, OnlyFixable: true},
+			wantPassed: true,
+			wantRules:  map[string]int{RuleFailOn: 0},
+		},
+		{
+			name:       "baseline ignores known findings",
+			policy:     Policy{FailOn: SeverityHigh, Baseline: []Finding{findings[0], findings[2], {VulnID: "CVE-2", PackageName: "zlib", PackageType: "deb", PackageVersion: "1.2.12"}}},
+			wantPassed: true,
+			wantRules:  map[string]int{RuleFailOn: 0},
+		},
+		{
+			name:      "coverage",
+			policy:    Policy{MinCoverage: 90},
+			wantRules: map[string]int{RuleMinCoverage: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Evaluate(findings, cov, tt.policy)
+			if v.Passed != tt.wantPassed {
+				t.Fatalf("passed = %v, rules: %+v", v.Passed, v.Rules)
+			}
+			if (v.Err() == nil) != tt.wantPassed {
+				t.Fatalf("Err() = %v disagrees with Passed", v.Err())
+			}
+			if len(v.Rules) != len(tt.wantRules) {
+				t.Fatalf("expected rules %v, got %+v", tt.wantRules, v.Rules)
+			}
+			for _, r := range v.Rules {
+				n, ok := tt.wantRules[r.Rule]
+				if !ok || len(r.Findings) != n {
+					t.Errorf("rule %s tripped on %d findings, want %d", r.Rule, len(r.Findings), n)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluate_MultipleViolations(t *testing.T) {
+	v := Evaluate(policyFindings(), nil, Policy{FailOn: SeverityCritical, FailOnScore: 9})
+	err := v.Err()
+	if err == nil || !strings.HasPrefix(err.Error(), "policy violation: ") || !strings.Contains(err.Error(), "CVSS score >= 9.0") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !v.Violates(policyFindings()[0]) || v.Violates(policyFindings()[1]) {
+		t.Fatal("Violates should match only the critical finding")
+	}
+}
+
+func TestWriteJSON_IncludesVerdict(t *testing.T) {
+	findings := policyFindings()
+	v := Evaluate(findings, nil, Policy{FailOn: SeverityCritical})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, ScanReport{Image: "img", Summary: Summarize(findings), Findings: findings, Verdict: &v}); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Verdict Verdict `json:"verdict"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Verdict.Passed || len(out.Verdict.Rules) != 1 || out.Verdict.Rules[0].Findings[0].VulnID != "CVE-1" {
+		t.Fatalf("unexpected verdict: %+v", out.Verdict)
+	}
+
+	// the baseline loader reads the same report back
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil || len(baseline) != len(findings) {
+		t.Fatalf("LoadBaseline: %d findings, %v", len(baseline), err)
+	}
+	if _, err := LoadBaseline(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected error for missing baseline")
+	}
+}
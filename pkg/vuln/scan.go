@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/kiptoonkipkurui/provavalidator/pkg/cvss"
 	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
 )
 
@@ -14,27 +16,54 @@ type ScanOptions struct {
 	// If true, packages without version are skipped (OSV version queries need a version).
 	RequireVersion bool
 
-	// If true, skip packages that lack PURL and cant be mapped
+	// If true, skip packages that lack PURL instead of querying by
+	// ecosystem and name
 	RequirePURL bool
+
+	// Distro qualifies OS packages (deb/apk/rpm) that carry no release of
+	// their own, e.g. Debian:12. Usually ResolvedSBOM.Distro.
+	Distro *sbom.Distro
+
+	// Graph fills Finding.IntroducedVia. Usually ResolvedSBOM.Graph().
+	Graph *sbom.Graph
+
+	// CVSSPreference orders CVSS versions when a vulnerability has several
+	// vectors. Defaults to cvss.DefaultPreference.
+	CVSSPreference []cvss.Version
+
+	// SeverityPrecedence picks between CVSS and vendor ratings (default CVSS first).
+	SeverityPrecedence SeverityPrecedence
+
+	// Offline matches against the local OSV database instead of the API.
+	Offline bool
+
+	// DBPath is the local database location (default under the user cache dir).
+	DBPath string
+
+	// NoCache skips the on-disk OSV and SBOM caches; CacheTTL overrides
+	// DefaultCacheTTL for OSV results.
+	NoCache  bool
+	CacheTTL time.Duration
 }
 
-func ScanNormalizedPackagesWithOSV(ctx context.Context, client *OSVClient, pkgs []sbom.NormalizedPackage, opts ScanOptions) ([]Finding, error) {
+func ScanNormalizedPackagesWithOSV(ctx context.Context, client *OSVClient, pkgs []sbom.NormalizedPackage, opts ScanOptions) (*ScanResult, error) {
 	if client == nil {
 		client = NewOSVClient()
 	}
 
+	res := &ScanResult{Coverage: newCoverage()}
 	queries := make([]osvQuery, 0, len(pkgs))
 	index := make([]sbom.NormalizedPackage, 0, len(pkgs)) // track which query maps to which package
 
 	for _, p := range pkgs {
-		q, ok, reason := toOSVQuery(p, opts)
-
-		if !ok {
+		q, err := toOSVQuery(p, opts)
+		if err != nil {
 			// not an error; just not queryable under current policy
-			_ = reason
+			res.Coverage.skip(p, err)
 			continue
 		}
 
+		res.Coverage.queried(p)
 		queries = append(queries, q)
 		index = append(index, p)
 	}
@@ -44,39 +73,127 @@ func ScanNormalizedPackagesWithOSV(ctx context.Context, client *OSVClient, pkgs
 		return nil, err
 	}
 
-	findings := make([]Finding, 0)
+	// querybatch only returns IDs; fetch the full records once per ID
+	ids := make([]string, 0)
+	for _, r := range results {
+		for _, v := range r.Vulns {
+			ids = append(ids, v.ID)
+		}
+	}
+	records, err := client.HydrateVulns(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Findings = make([]Finding, 0)
 
 	for i, r := range results {
 		p := index[i]
 		for _, v := range r.Vulns {
-			score, sev := bestEffortSeverity(v.Severity)
-
-			findings = append(findings, Finding{
-				PackageName:    p.Name,
-				PackageVersion: p.Version,
-				PURL:           p.PURL,
-				VulnID:         v.ID,
-				Summary:        v.Summary,
-				Details:        v.Details,
-				Aliases:        v.Aliases,
-				CVSSScore:      score,
-				Severity:       sev,
-			})
+			if full, ok := records[v.ID]; ok {
+				v = full
+			}
+			res.Findings = append(res.Findings, newFinding(p, v, opts))
+		}
+	}
+	res.Findings = Deduplicate(res.Findings)
+
+	return res, nil
+}
+
+// ScanNormalizedPackagesOffline matches packages against a local OSV
+// database. Candidate records are looked up by ecosystem and name, then
+// evaluated against the package version.
+func ScanNormalizedPackagesOffline(ctx context.Context, db *LocalDB, pkgs []sbom.NormalizedPackage, opts ScanOptions) (*ScanResult, error) {
+	res := &ScanResult{Findings: make([]Finding, 0), Coverage: newCoverage()}
+
+	for _, p := range pkgs {
+		c, err := packageCoordinates(p, opts)
+		if err != nil {
+			res.Coverage.skip(p, err)
+			continue
+		}
+		res.Coverage.queried(p)
+
+		coords := []osvCoordinates{c}
+		if u, ok := upstreamCoordinates(p, c); ok {
+			coords = append(coords, u)
+		}
+		seen := map[string]struct{}{}
+		for _, c := range coords {
+			candidates, err := db.lookup(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range candidates {
+				if _, ok := seen[v.ID]; ok || !affectsVersion(v, c) {
+					continue
+				}
+				seen[v.ID] = struct{}{}
+				res.Findings = append(res.Findings, newFinding(p, v, opts))
+			}
 		}
 	}
+	res.Findings = Deduplicate(res.Findings)
 
-	return findings, nil
+	return res, nil
+}
+
+func newFinding(p sbom.NormalizedPackage, v osvVulnerability, opts ScanOptions) Finding {
+	score, sev, src := resolveSeverity(v, p, opts)
+	fixed := fixedVersions(v, p)
+
+	return Finding{
+		PackageName:    p.Name,
+		PackageVersion: p.Version,
+		PackageType:    p.Type,
+		PURL:           p.PURL,
+		VulnID:         v.ID,
+		Summary:        v.Summary,
+		Details:        v.Details,
+		Aliases:        v.Aliases,
+		CVSSScore:      score.Base,
+		CVSSVector:     score.Vector,
+		CVSSVersion:    string(score.Version),
+		Severity:       sev,
+		SeveritySource: src,
+		FixedVersions:  fixed,
+		FixAvailable:   len(fixed) > 0,
+		Locations:      p.Locations,
+		IntroducedVia:  introducedVia(opts.Graph, p),
+	}
+}
+
+// maxIntroducedVia caps the chains reported per finding; widely shared
+// libraries can be reached through hundreds.
+const maxIntroducedVia = 3
+
+func introducedVia(g *sbom.Graph, p sbom.NormalizedPackage) []string {
+	if g == nil || p.ID == "" {
+		return nil
+	}
+	var out []string
+	for _, path := range g.Paths(p.ID, maxIntroducedVia) {
+		names := make([]string, 0, len(path))
+		for _, q := range path {
+			names = append(names, strings.TrimSpace(q.Name+" "+q.Version))
+		}
+		out = append(out, strings.Join(names, " > "))
+	}
+	return out
 }
 
 // OSV query rules: use either top-level version or versioned PURL not both. :contentReference[oaicite:2]{index=2}
-func toOSVQuery(p sbom.NormalizedPackage, opts ScanOptions) (osvQuery, bool, string) {
+// The returned error explains why a package is not queryable; it is a skip,
+// not a failure.
+func toOSVQuery(p sbom.NormalizedPackage, opts ScanOptions) (osvQuery, error) {
 	if opts.RequireVersion && strings.TrimSpace(p.Version) == "" && !purlHasVersion(p.PURL) {
-		return osvQuery{}, false, "missing version"
+		return osvQuery{}, skip(SkipMissingVersion, "")
 	}
 
 	purl := strings.TrimSpace(p.PURL)
 	if purl == "" && opts.RequirePURL {
-		return osvQuery{}, false, "missing purl"
+		return osvQuery{}, skip(SkipMissingPURL, "")
 	}
 
 	// Best practice: prefer PURL queries when available (ecosystem mapping is painful for OS pkgs).
@@ -86,25 +203,30 @@ func toOSVQuery(p sbom.NormalizedPackage, opts ScanOptions) (osvQuery, bool, str
 				Package: &osvPackage{
 					PURL: purl,
 				},
-			}, true, ""
+			}, nil
 		}
 
 		ver := strings.TrimSpace(p.Version)
 		if ver == "" && opts.RequireVersion {
-			return osvQuery{}, false, "missing version for unversioned purl"
+			return osvQuery{}, skip(SkipMissingVersion, "unversioned purl")
 		}
 
 		q := osvQuery{Package: &osvPackage{PURL: purl}}
 		if ver != "" {
 			q.Version = ver
 		}
-		return q, true, ""
+		return q, nil
 	}
 
-	// Fallsback: name+ecosystem
-	// Fallback: name+ecosystem (only if you implement mapping elsewhere).
-	// Keep this disabled by default; you can add ecosystem mapping later.
-	return osvQuery{}, false, "no purl; ecosystem mapping not enabled"
+	// Fallback: name+ecosystem from the Syft package type
+	c, err := coordinatesFromType(p, opts.Distro)
+	if err != nil {
+		return osvQuery{}, err
+	}
+	return osvQuery{
+		Package: &osvPackage{Name: c.Name, Ecosystem: c.Ecosystem},
+		Version: c.Version,
+	}, nil
 }
 
 func purlHasVersion(purl string) bool {
@@ -114,25 +236,48 @@ func purlHasVersion(purl string) bool {
 	return i > 0 && i < len(purl)-1
 }
 
-func bestEffortSeverity(entries []osvSeverityEntry) (score float64, sev Severity) {
-	// Prefer numeric "score" if present; else unknown.
-	best := -1.0
+// bestEffortSeverity picks one score out of OSV's severity entries.
+// Vectors are scored and chosen by version preference (highest score wins
+// within a version); plain numeric scores are only a fallback.
+func bestEffortSeverity(entries []osvSeverityEntry, pref []cvss.Version) (cvss.Score, Severity) {
+	if len(pref) == 0 {
+		pref = cvss.DefaultPreference
+	}
+
+	var best cvss.Score
+	found := false
+	numeric := -1.0
 	for _, e := range entries {
 		s := strings.TrimSpace(e.Score)
 		if s == "" {
 			continue
 		}
-		// Some OSV responses use numeric strings; some may be vectors.
+		// Some OSV responses use numeric strings; most are vectors.
 		if f, err := strconv.ParseFloat(s, 64); err == nil {
-			if f > best {
-				best = f
+			if f > numeric {
+				numeric = f
 			}
+			continue
 		}
+
+		sc, err := cvss.Parse(s)
+		if err != nil {
+			continue
+		}
+		if !found || cvss.Rank(sc.Version, pref) < cvss.Rank(best.Version, pref) ||
+			(sc.Version == best.Version && sc.Base > best.Base) {
+			best = sc
+			found = true
+		}
+	}
+
+	if found {
+		return best, cvssToSeverity(best.Base)
 	}
-	if best < 0 {
-		return 0, SeverityUnknown
+	if numeric >= 0 {
+		return cvss.Score{Base: numeric}, cvssToSeverity(numeric)
 	}
-	return best, cvssToSeverity(best)
+	return cvss.Score{}, SeverityUnknown
 }
 
 func cvssToSeverity(score float64) Severity {
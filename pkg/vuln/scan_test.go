@@ -5,14 +5,32 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"github.com/kiptoonkipkurui/provavalidator/pkg/cvss"
 	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
 )
 
 func TestScanNormalizedPackagesWithOSV_QueryBatchAndFindings(t *testing.T) {
-	// Fake OSV server
+	var fetches atomic.Int32
+
+	// Fake OSV server: like the real API, querybatch only returns IDs and
+	// the full record has to be fetched from /v1/vulns/{id}.
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/v1/vulns/OSV-2025-TEST" {
+			fetches.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(osvVulnerability{
+				ID:      "OSV-2025-TEST",
+				Summary: "test vuln",
+				Severity: []osvSeverityEntry{
+					{Type: "CVSS_V3", Score: "9.8"},
+				},
+			})
+			return
+		}
+
 		if r.Method != http.MethodPost || r.URL.Path != "/v1/querybatch" {
 			w.WriteHeader(http.StatusNotFound)
 			return
@@ -39,13 +57,7 @@ func TestScanNormalizedPackagesWithOSV_QueryBatchAndFindings(t *testing.T) {
 		for i := range req.Queries {
 			resp.Results[i] = osvQueryResult{
 				Vulns: []osvVulnerability{
-					{
-						ID:      "OSV-2025-TEST",
-						Summary: "test vuln",
-						Severity: []osvSeverityEntry{
-							{Type: "CVSS_V3", Score: "9.8"},
-						},
-					},
+					{ID: "OSV-2025-TEST", Modified: "2025-01-01T00:00:00Z"},
 				},
 			}
 		}
@@ -66,13 +78,14 @@ func TestScanNormalizedPackagesWithOSV_QueryBatchAndFindings(t *testing.T) {
 		{Name: "requests", Version: "2.32.0", Type: "python", PURL: "pkg:pypi/requests@2.32.0"},
 	}
 
-	findings, err := ScanNormalizedPackagesWithOSV(context.Background(), client, pkgs, ScanOptions{
+	res, err := ScanNormalizedPackagesWithOSV(context.Background(), client, pkgs, ScanOptions{
 		RequireVersion: true,
 		RequirePURL:    true,
 	})
 	if err != nil {
 		t.Fatalf("scan failed: %v", err)
 	}
+	findings := res.Findings
 
 	if len(findings) != 2 {
 		t.Fatalf("expected 2 findings, got %d", len(findings))
@@ -81,4 +94,153 @@ func TestScanNormalizedPackagesWithOSV_QueryBatchAndFindings(t *testing.T) {
 	if findings[0].Severity != SeverityCritical {
 		t.Fatalf("expected critical severity, got %s", findings[0].Severity)
 	}
+
+	if findings[0].Summary != "test vuln" {
+		t.Fatalf("expected hydrated summary, got %q", findings[0].Summary)
+	}
+
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("expected 1 fetch for the shared vuln ID, got %d", n)
+	}
+}
+
+func TestScanNormalizedPackagesWithOSV_EcosystemFallback(t *testing.T) {
+	var got []osvQuery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req osvQueryBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = append(got, req.Queries...)
+		_ = json.NewEncoder(w).Encode(osvQueryBatchResponse{Results: make([]osvQueryResult, len(req.Queries))})
+	}))
+	defer srv.Close()
+
+	client := NewOSVClient()
+	client.BaseURl = srv.URL
+
+	pkgs := []sbom.NormalizedPackage{
+		{Name: "requests", Version: "2.32.0", Type: "python", PURL: "pkg:pypi/requests@2.32.0"},
+		{Name: "golang.org/x/net", Version: "v0.17.0", Type: "go-module"},
+		{Name: "libssl3", Version: "3.0.11-1~deb12u2", Type: "deb"},
+		{Name: "busybox", Version: "1.36.1", Type: "binary"},
+		{Name: "commons-text", Version: "1.9", Type: "java-archive"},
+		{Name: "left-pad", Type: "npm"},
+	}
+	opts := DefaultScanOptions()
+	opts.Distro = &sbom.Distro{ID: "debian", VersionID: "12"}
+
+	res, err := ScanNormalizedPackagesWithOSV(context.Background(), client, pkgs, opts)
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 queries, got %d: %+v", len(got), got)
+	}
+	if q := got[1]; q.Package.Ecosystem != "Go" || q.Package.Name != "golang.org/x/net" || q.Version != "v0.17.0" {
+		t.Fatalf("unexpected go query: %+v %+v", q, q.Package)
+	}
+	if q := got[2]; q.Package.Ecosystem != "Debian:12" || q.Package.Name != "libssl3" {
+		t.Fatalf("unexpected deb query: %+v", q.Package)
+	}
+
+	cov := res.Coverage
+	if cov.Packages != 6 || cov.Queryable != 3 || len(cov.Skipped) != 3 {
+		t.Fatalf("unexpected coverage: %+v", cov)
+	}
+	want := []SkipReason{SkipUnsupportedEcosystem, SkipMissingPURL, SkipMissingVersion}
+	for i, s := range cov.Skipped {
+		if s.Reason != want[i] {
+			t.Errorf("skipped[%d] %s: expected reason %q, got %q (%s)", i, s.Name, want[i], s.Reason, s.Detail)
+		}
+	}
+}
+
+func TestScanNormalizedPackagesWithOSV_UnknownDistro(t *testing.T) {
+	// no distro: OS packages without PURL are skipped before any request
+	res, err := ScanNormalizedPackagesWithOSV(context.Background(), &OSVClient{BaseURl: "http://127.0.0.1:0"},
+		[]sbom.NormalizedPackage{{Name: "musl", Version: "1.2.4-r2", Type: "apk"}}, DefaultScanOptions())
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(res.Coverage.Skipped) != 1 || res.Coverage.Skipped[0].Reason != SkipUnknownDistro {
+		t.Fatalf("unexpected coverage: %+v", res.Coverage)
+	}
+}
+
+func TestBestEffortSeverity_Vectors(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []osvSeverityEntry
+		pref        []cvss.Version
+		wantScore   float64
+		wantVersion cvss.Version
+		wantSev     Severity
+	}{
+		{
+			name:        "v3.1 vector",
+			entries:     []osvSeverityEntry{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+			wantScore:   9.8,
+			wantVersion: cvss.V31,
+			wantSev:     SeverityCritical,
+		},
+		{
+			name: "v4 preferred over v3 by default",
+			entries: []osvSeverityEntry{
+				{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+				{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:A/VC:L/VI:L/VA:N/SC:N/SI:N/SA:N"},
+			},
+			wantScore:   5.1,
+			wantVersion: cvss.V40,
+			wantSev:     SeverityMedium,
+		},
+		{
+			name: "custom preference",
+			entries: []osvSeverityEntry{
+				{Type: "CVSS_V2", Score: "AV:N/AC:M/Au:N/C:P/I:N/A:N"},
+				{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:A/VC:L/VI:L/VA:N/SC:N/SI:N/SA:N"},
+			},
+			pref:        []cvss.Version{cvss.V2, cvss.V40},
+			wantScore:   4.3,
+			wantVersion: cvss.V2,
+			wantSev:     SeverityMedium,
+		},
+		{
+			name:    "garbage is unknown",
+			entries: []osvSeverityEntry{{Type: "CVSS_V3", Score: "CVSS:3.1/nope"}},
+			wantSev: SeverityUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, sev := bestEffortSeverity(tt.entries, tt.pref)
+			if score.Base != tt.wantScore || score.Version != tt.wantVersion || sev != tt.wantSev {
+				t.Fatalf("got %.1f/%s/%s, want %.1f/%s/%s", score.Base, score.Version, sev, tt.wantScore, tt.wantVersion, tt.wantSev)
+			}
+		})
+	}
+}
+
+func TestNewFinding_IntroducedVia(t *testing.T) {
+	pkgs := []sbom.NormalizedPackage{
+		{ID: "app", Name: "app", Version: "1.0.0", Type: "npm"},
+		{ID: "express", Name: "express", Version: "4.18.2", Type: "npm"},
+		{ID: "qs", Name: "qs", Version: "6.11.0", Type: "npm", PURL: "pkg:npm/qs@6.11.0"},
+	}
+	opts := DefaultScanOptions()
+	opts.Graph = sbom.NewGraph(pkgs, []sbom.Relationship{
+		{From: "app", To: "express", Type: sbom.DependsOn},
+		{From: "express", To: "qs", Type: sbom.DependsOn},
+	})
+
+	f := newFinding(pkgs[2], osvVulnerability{ID: "CVE-2022-24999"}, opts)
+	if len(f.IntroducedVia) != 1 || f.IntroducedVia[0] != "app 1.0.0 > express 4.18.2" {
+		t.Fatalf("unexpected introducedVia: %q", f.IntroducedVia)
+	}
+	if f := newFinding(pkgs[0], osvVulnerability{ID: "X"}, opts); f.IntroducedVia != nil {
+		t.Fatalf("expected no paths for a top-level package, got %q", f.IntroducedVia)
+	}
 }
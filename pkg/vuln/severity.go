@@ -0,0 +1,162 @@
+package vuln
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/cvss"
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+)
+
+// SeverityPrecedence decides which source wins when both a CVSS vector and
+// a vendor rating are available.
+type SeverityPrecedence string
+
+const (
+	PreferCVSS   SeverityPrecedence = "cvss"
+	PreferVendor SeverityPrecedence = "vendor"
+)
+
+func ParseSeverityPrecedence(s string) (SeverityPrecedence, error) {
+	switch strings.ToLower(s) {
+	case "", "cvss":
+		return PreferCVSS, nil
+	case "vendor":
+		return PreferVendor, nil
+	default:
+		return "", fmt.Errorf("invalid severity source %q (use cvss or vendor)", s)
+	}
+}
+
+// SeveritySource records where a finding's severity came from.
+type SeveritySource string
+
+const (
+	SeveritySourceCVSS      SeveritySource = "cvss"
+	SeveritySourceEcosystem SeveritySource = "ecosystem_specific"
+	SeveritySourceDatabase  SeveritySource = "database_specific"
+)
+
+// resolveSeverity combines the CVSS score and any vendor rating according
+// to the configured precedence, falling back to whichever one exists.
+func resolveSeverity(v osvVulnerability, p sbom.NormalizedPackage, opts ScanOptions) (cvss.Score, Severity, SeveritySource) {
+	score, sev := bestEffortSeverity(v.Severity, opts.CVSSPreference)
+	vsev, vsrc := vendorSeverity(v, p)
+
+	switch {
+	case opts.SeverityPrecedence == PreferVendor && vsev != SeverityUnknown:
+		return score, vsev, vsrc
+	case sev != SeverityUnknown:
+		return score, sev, SeveritySourceCVSS
+	case vsev != SeverityUnknown:
+		return score, vsev, vsrc
+	}
+	return score, SeverityUnknown, ""
+}
+
+// vendorSeverity looks for a non-CVSS rating. Ecosystem-specific data on the
+// affected entry for this package is the most specific, then OSV severity
+// entries that carry a label (type "Ubuntu"), then database-specific data.
+// Entries for other packages or releases are never consulted.
+func vendorSeverity(v osvVulnerability, p sbom.NormalizedPackage) (Severity, SeveritySource) {
+	affected := make([]osvAffected, 0, len(v.Affected))
+	for _, a := range v.Affected {
+		if affectsPackage(a, p) {
+			affected = append(affected, a)
+		}
+	}
+
+	for _, a := range affected {
+		if s := severityFromSpecific(a.EcosystemSpecific); s != SeverityUnknown {
+			return s, SeveritySourceEcosystem
+		}
+	}
+	for _, e := range v.Severity {
+		if strings.HasPrefix(e.Score, "CVSS") {
+			continue
+		}
+		if s := parseVendorSeverity(e.Score); s != SeverityUnknown {
+			return s, SeveritySourceEcosystem
+		}
+	}
+	if s := severityFromSpecific(v.DatabaseSpecific); s != SeverityUnknown {
+		return s, SeveritySourceDatabase
+	}
+	for _, a := range affected {
+		if s := severityFromSpecific(a.DatabaseSpecific); s != SeverityUnknown {
+			return s, SeveritySourceDatabase
+		}
+	}
+	return SeverityUnknown, ""
+}
+
+// affectsPackage reports whether an affected entry describes p. Entries for
+// another release of p's distro are not. With PURLs on both sides the type,
+// namespace and name are compared, qualifiers and version ignored; otherwise
+// the name. Either way the name may also be p's distro source package,
+// which is what Debian, Ubuntu and Alpine file advisories under.
+func affectsPackage(a osvAffected, p sbom.NormalizedPackage) bool {
+	c, cerr := coordinatesFromPURL(p.PURL, nil)
+	if cerr == nil && a.Package.Ecosystem != "" && !ecosystemMatches(a.Package.Ecosystem, c.Ecosystem) {
+		return false
+	}
+
+	names := []string{p.Name}
+	if cerr == nil {
+		names = append(names, c.Name)
+	}
+	if p.Upstream != nil {
+		names = append(names, p.Upstream.Name)
+	}
+
+	if a.Package.PURL != "" && p.PURL != "" {
+		ap, aerr := packageurl.FromString(a.Package.PURL)
+		pp, perr := packageurl.FromString(p.PURL)
+		if aerr == nil && perr == nil {
+			if !strings.EqualFold(ap.Type, pp.Type) || !strings.EqualFold(ap.Namespace, pp.Namespace) {
+				return false
+			}
+			return nameIn(purlEcosystems[ap.Type], ap.Name, names)
+		}
+	}
+	return a.Package.Name != "" && nameIn(a.Package.Ecosystem, a.Package.Name, names)
+}
+
+func nameIn(ecosystem, name string, names []string) bool {
+	name = normalizeName(ecosystem, name)
+	for _, n := range names {
+		if n != "" && normalizeName(ecosystem, n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func severityFromSpecific(m map[string]any) Severity {
+	for _, key := range []string{"severity", "urgency"} {
+		if s, ok := m[key].(string); ok {
+			if sev := parseVendorSeverity(s); sev != SeverityUnknown {
+				return sev
+			}
+		}
+	}
+	return SeverityUnknown
+}
+
+// parseVendorSeverity maps the labels used by GHSA, Debian, Ubuntu, Red Hat
+// and friends onto our buckets. Unrecognized labels stay unknown.
+func parseVendorSeverity(s string) Severity {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical":
+		return SeverityCritical
+	case "high", "important":
+		return SeverityHigh
+	case "medium", "moderate":
+		return SeverityMedium
+	case "low", "negligible", "unimportant":
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
@@ -0,0 +1,128 @@
+package vuln
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kiptoonkipkurui/provavalidator/pkg/sbom"
+)
+
+func TestResolveSeverity_Precedence(t *testing.T) {
+	// Trimmed-down OSV record: CVSS says critical, Debian says low.
+	raw := `{
+		"id": "DSA-0000-1",
+		"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+		"affected": [
+			{"package": {"name": "curl", "ecosystem": "Debian:12"}, "ecosystem_specific": {"urgency": "high"}},
+			{"package": {"name": "openssl", "ecosystem": "Debian:12"}, "ecosystem_specific": {"urgency": "low"}}
+		],
+		"database_specific": {"severity": "MODERATE"}
+	}`
+	var v osvVulnerability
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+	p := sbom.NormalizedPackage{Name: "openssl", Version: "3.0.2", Type: "deb"}
+
+	_, sev, src := resolveSeverity(v, p, ScanOptions{})
+	if sev != SeverityCritical || src != SeveritySourceCVSS {
+		t.Fatalf("cvss first: got %s from %s", sev, src)
+	}
+
+	_, sev, src = resolveSeverity(v, p, ScanOptions{SeverityPrecedence: PreferVendor})
+	if sev != SeverityLow || src != SeveritySourceEcosystem {
+		t.Fatalf("vendor first: got %s from %s", sev, src)
+	}
+
+	// No CVSS at all: vendor data is used even with CVSS precedence.
+	v.Severity = nil
+	v.Affected = nil
+	_, sev, src = resolveSeverity(v, p, ScanOptions{})
+	if sev != SeverityMedium || src != SeveritySourceDatabase {
+		t.Fatalf("fallback: got %s from %s", sev, src)
+	}
+}
+
+func TestAffectsPackage(t *testing.T) {
+	// As published by OSV for Debian: keyed by source package, one entry
+	// per release, with an arch=source PURL.
+	raw := `{
+		"id": "DEBIAN-CVE-2023-5678",
+		"aliases": ["CVE-2023-5678"],
+		"affected": [
+			{
+				"package": {"name": "openssl", "ecosystem": "Debian:11", "purl": "pkg:deb/debian/openssl?arch=source"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.1.1w-0+deb11u2"}]}],
+				"ecosystem_specific": {"urgency": "high"}
+			},
+			{
+				"package": {"name": "openssl", "ecosystem": "Debian:12", "purl": "pkg:deb/debian/openssl?arch=source"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.13-1~deb12u1"}]}],
+				"ecosystem_specific": {"urgency": "low"}
+			}
+		]
+	}`
+	var v osvVulnerability
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	openssl := sbom.NormalizedPackage{
+		Name: "openssl", Version: "3.0.11-1~deb12u2", Type: "deb",
+		PURL: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?arch=amd64&distro=debian-12",
+	}
+	libssl := sbom.NormalizedPackage{
+		Name: "libssl3", Version: "3.0.11-1~deb12u2", Type: "deb",
+		PURL:     "pkg:deb/debian/libssl3@3.0.11-1~deb12u2?arch=amd64&distro=debian-12",
+		Upstream: &sbom.Upstream{Name: "openssl"},
+	}
+	for _, p := range []sbom.NormalizedPackage{openssl, libssl} {
+		if affectsPackage(v.Affected[0], p) {
+			t.Errorf("%s: Debian:11 entry matched a Debian 12 package", p.Name)
+		}
+		if !affectsPackage(v.Affected[1], p) {
+			t.Errorf("%s: Debian:12 entry did not match", p.Name)
+		}
+		if sev, _ := vendorSeverity(v, p); sev != SeverityLow {
+			t.Errorf("%s: vendor severity %s, want low from the Debian:12 entry", p.Name, sev)
+		}
+	}
+
+	// another release's urgency never stands in for a missing one
+	other := v
+	other.Affected = v.Affected[:1]
+	if sev, _ := vendorSeverity(other, openssl); sev != SeverityUnknown {
+		t.Errorf("vendor severity %s from a Debian:11 entry, want unknown", sev)
+	}
+
+	// the same package without a release qualifier matches either release
+	bare := sbom.NormalizedPackage{Name: "openssl", Type: "deb", PURL: "pkg:deb/debian/openssl@3.0.11-1~deb12u2"}
+	if !affectsPackage(v.Affected[0], bare) || !affectsPackage(v.Affected[1], bare) {
+		t.Error("package without distro qualifier should match every release")
+	}
+}
+
+func TestAffectsPackage_NamePrefix(t *testing.T) {
+	raw := `{
+		"id": "GHSA-0000-0000-0000",
+		"affected": [
+			{"package": {"name": "jinja2", "ecosystem": "PyPI", "purl": "pkg:pypi/jinja2"}, "database_specific": {"severity": "HIGH"}},
+			{"package": {"name": "jinja2-time", "ecosystem": "PyPI", "purl": "pkg:pypi/jinja2-time"}, "database_specific": {"severity": "LOW"}}
+		]
+	}`
+	var v osvVulnerability
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+	p := sbom.NormalizedPackage{Name: "jinja2-time", Version: "0.2.0", Type: "python", PURL: "pkg:pypi/jinja2-time@0.2.0"}
+
+	if affectsPackage(v.Affected[0], p) {
+		t.Error("pkg:pypi/jinja2 matched jinja2-time")
+	}
+	if !affectsPackage(v.Affected[1], p) {
+		t.Error("pkg:pypi/jinja2-time did not match jinja2-time")
+	}
+	if sev, _ := vendorSeverity(v, p); sev != SeverityLow {
+		t.Errorf("vendor severity %s, want low from the jinja2-time entry", sev)
+	}
+}
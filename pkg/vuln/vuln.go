@@ -15,6 +15,9 @@ type Summary struct {
 	Medium   int
 	Low      int
 	Unknown  int
+
+	// KnownExploited counts findings in the KEV catalog, whatever their severity.
+	KnownExploited int
 }
 
 func Summarize(findings []Finding) Summary {
@@ -34,45 +37,84 @@ func Summarize(findings []Finding) Summary {
 		default:
 			s.Unknown++
 		}
+		if f.KnownExploited != nil {
+			s.KnownExploited++
+		}
 	}
 	return s
 }
 
+// DefaultScanOptions are the options ScanVulnerabilities uses.
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		RequireVersion: true,
+	}
+}
+
 func ScanVulnerabilities(ctx context.Context, image string) ([]Finding, error) {
-	// TODO: integrate Grype or OSV queries
-	fmt.Println("[vuln] Scanning vulnerabilities for:", image)
+	res, err := ScanVulnerabilitiesWithOptions(ctx, image, DefaultScanOptions())
+	if err != nil {
+		return nil, err
+	}
+	return res.Findings, nil
+}
 
-	resSbom, err := sbom.ExtractSBOM(ctx, image)
+func ScanVulnerabilitiesWithOptions(ctx context.Context, image string, opts ScanOptions) (*ScanResult, error) {
+	resSbom, err := sbom.ExtractSBOMWithOptions(ctx, image, sbom.ExtractOptions{NoCache: opts.NoCache})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract SBOM: %w", err)
 	}
-
-	client := NewOSVClient()
-	findings, err := ScanNormalizedPackagesWithOSV(ctx, client, resSbom.Packages, ScanOptions{
-		RequireVersion: true,
-		RequirePURL:    true,
-	})
-	return findings, err
+	return ScanSBOM(ctx, resSbom, opts)
 }
 
-type Policy struct {
-	FailOn Severity
-}
+// ScanSBOM scans the packages of an SBOM the caller already has, taking the
+// distro and dependency graph from it unless opts sets them.
+func ScanSBOM(ctx context.Context, resSbom *sbom.ResolvedSBOM, opts ScanOptions) (*ScanResult, error) {
+	if opts.Distro == nil {
+		opts.Distro = resSbom.Distro
+	}
+	if opts.Graph == nil {
+		opts.Graph = resSbom.Graph()
+	}
 
-func EnforcePolicy(findings []Finding, p Policy) error {
-	for _, f := range findings {
-		if severityRank(f.Severity) >= severityRank(p.FailOn) {
-			return fmt.Errorf(
-				"policy violation: %s vulnerability %s in %s@%s",
-				f.Severity,
-				f.VulnID,
-				f.PackageName,
-				f.PackageVersion,
-			)
+	if opts.Offline {
+		db, err := OpenLocalDB(opts.DBPath)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+
+		st, err := db.Status(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if st.Records == 0 {
+			return nil, fmt.Errorf("osv db: %s is empty; run `vuln db import` first", db.Path)
+		}
+		res, err := ScanNormalizedPackagesOffline(ctx, db, resSbom.Packages, opts)
+		return withSBOMSource(res, resSbom), err
+	}
+
+	client := NewOSVClient()
+	if !opts.NoCache {
+		cache, err := OpenCache("", opts.CacheTTL)
+		if err != nil {
+			return nil, err
 		}
+		client.Cache = cache
 	}
-	return nil
+	res, err := ScanNormalizedPackagesWithOSV(ctx, client, resSbom.Packages, opts)
+	return withSBOMSource(res, resSbom), err
+}
+
+func withSBOMSource(res *ScanResult, s *sbom.ResolvedSBOM) *ScanResult {
+	if res != nil {
+		res.SBOM = s
+		res.Coverage.SBOMSource = s.Source
+		res.Coverage.SBOMFormat = s.Format
+	}
+	return res
 }
 
 func severityRank(s Severity) int {
@@ -91,15 +133,68 @@ func severityRank(s Severity) int {
 }
 func FormatFinding(f Finding) string {
 	return fmt.Sprintf(
-		"- [%s] %s (%s)\n  Package: %s@%s\n  %s\n",
+		"- [%s] %s (%s)\n  Package: %s@%s\n%s%s%s  Fix: %s\n  %s\n",
 		f.Severity,
 		f.VulnID,
 		f.Summary,
 		f.PackageName,
 		f.PackageVersion,
+		formatSeveritySource(f),
+		formatIntroducedVia(f),
+		formatExploit(f),
+		formatFix(f),
 		f.Details,
 	)
 }
+
+func formatFix(f Finding) string {
+	if !f.FixAvailable {
+		return "no fix available"
+	}
+	return "upgrade to " + strings.Join(f.FixedVersions, " or ")
+}
+
+func formatExploit(f Finding) string {
+	var out string
+	if k := f.KnownExploited; k != nil {
+		out += "  Known exploited: yes (CISA KEV"
+		if k.DateAdded != "" {
+			out += ", added " + k.DateAdded
+		}
+		if k.DueDate != "" {
+			out += ", due " + k.DueDate
+		}
+		if k.KnownRansomware {
+			out += ", used in ransomware"
+		}
+		out += ")\n"
+	}
+	if e := f.EPSS; e != nil {
+		out += fmt.Sprintf("  EPSS: %.1f%% (percentile %.0f)\n", e.Probability*100, e.Percentile*100)
+	}
+	return out
+}
+
+func formatIntroducedVia(f Finding) string {
+	var out string
+	for _, p := range f.IntroducedVia {
+		out += "  Introduced via: " + p + "\n"
+	}
+	return out
+}
+
+func formatSeveritySource(f Finding) string {
+	switch {
+	case f.SeveritySource == SeveritySourceCVSS && f.CVSSVector != "":
+		return fmt.Sprintf("  Severity: CVSS %s %.1f (%s)\n", f.CVSSVersion, f.CVSSScore, f.CVSSVector)
+	case f.SeveritySource == SeveritySourceCVSS:
+		return fmt.Sprintf("  Severity: CVSS %.1f\n", f.CVSSScore)
+	case f.SeveritySource != "":
+		return fmt.Sprintf("  Severity: %s\n", f.SeveritySource)
+	default:
+		return ""
+	}
+}
 func ParseSeverity(s string) (Severity, error) {
 	switch strings.ToLower(s) {
 	case "critical":
@@ -3,54 +3,170 @@ package vuln
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 )
 
-func PrintText(image string, s Summary, findings []Finding, failOn string) error {
-	if failOn != "" {
-		level, err := ParseSeverity(failOn)
+// ScanReport is what the text and JSON outputs show for one scan.
+type ScanReport struct {
+	Image    string    `json:"image"`
+	Summary  Summary   `json:"summary"`
+	Findings []Finding `json:"findings"`
+	Coverage *Coverage `json:"coverage,omitempty"`
+	Verdict  *Verdict  `json:"verdict,omitempty"`
 
-		if err != nil {
-			return err
-		}
+	// Baseline names what Delta was computed against (a report file or image).
+	Baseline string `json:"baseline,omitempty"`
+	Delta    *Delta `json:"delta,omitempty"`
+}
 
-		violations := FilterBySeverity(findings, level)
+func PrintText(r ScanReport) error {
+	return WriteText(os.Stdout, r)
+}
 
-		if len(violations) > 0 {
-			fmt.Printf("Vulnerability policy violation (fail-on: %s)\n\n", failOn)
-			for _, v := range violations {
-				fmt.Println(FormatFinding(v))
+// WriteText writes the human-readable report. It only renders the verdict;
+// callers decide the exit status from Verdict.Err().
+func WriteText(w io.Writer, r ScanReport) error {
+	v := r.Verdict
+	switch {
+	case r.Delta != nil:
+		WriteDelta(w, r.Baseline, *r.Delta)
+	case v != nil && !v.Passed:
+		fmt.Fprintf(w, "Vulnerability policy violation\n\n")
+		for _, f := range r.Findings {
+			if v.Violates(f) {
+				fmt.Fprintln(w, FormatFinding(f))
 			}
+		}
+	}
+	WriteSummary(w, r.Summary)
+	WriteCoverage(w, r.Coverage)
+	WriteVerdict(w, v)
+	return nil
+}
 
-			PrintSummary(s)
+// WriteDelta lists new findings in full and fixed ones by name.
+func WriteDelta(w io.Writer, baseline string, d Delta) {
+	fmt.Fprintf(w, "Changes since %s:\n", baseline)
+	fmt.Fprintf(w, "  New:       %d\n", len(d.New))
+	fmt.Fprintf(w, "  Fixed:     %d\n", len(d.Fixed))
+	fmt.Fprintf(w, "  Unchanged: %d\n", len(d.Unchanged))
 
-			return fmt.Errorf("vulnerability policy violation: %d vulnerabilities found at or above severity %s", len(violations), failOn)
+	if len(d.New) > 0 {
+		fmt.Fprintf(w, "\nNew vulnerabilities:\n")
+		for _, f := range d.New {
+			fmt.Fprintln(w, FormatFinding(f))
 		}
 	}
-	PrintSummary(s)
-	return nil
+	if len(d.Fixed) > 0 {
+		fmt.Fprintf(w, "\nFixed vulnerabilities:\n")
+		for _, f := range d.Fixed {
+			fmt.Fprintf(w, "  - [%s] %s in %s@%s\n", f.Severity, f.VulnID, f.PackageName, f.PackageVersion)
+		}
+	}
+}
+
+// WriteVerdict lists each policy rule and whether it passed.
+func WriteVerdict(w io.Writer, v *Verdict) {
+	if v == nil || len(v.Rules) == 0 {
+		return
+	}
+	result := "PASSED"
+	if !v.Passed {
+		result = "FAILED"
+	}
+	fmt.Fprintf(w, "\nPolicy: %s\n", result)
+	for _, r := range v.Rules {
+		mark := "ok  "
+		if !r.Passed {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(w, "  [%s] %s: %s\n", mark, r.Rule, r.Message)
+	}
 }
+
 func PrintSummary(s Summary) {
-	fmt.Println("\nVulnerability summary:")
-	fmt.Printf("  Critical: %d\n", s.Critical)
-	fmt.Printf("  High:     %d\n", s.High)
-	fmt.Printf("  Medium:   %d\n", s.Medium)
-	fmt.Printf("  Low:      %d\n", s.Low)
-	fmt.Printf("  Total:    %d\n", s.Total)
-}
-
-func PrintJSON(image string, s Summary, findings []Finding, failOn string) error {
-	out := struct {
-		Image    string    `json:"image"`
-		Summary  Summary   `json:"summary"`
-		Findings []Finding `json:"findings"`
-	}{
-		Image:    image,
-		Summary:  s,
-		Findings: findings,
-	}
-
-	enc := json.NewEncoder(os.Stdout)
+	WriteSummary(os.Stdout, s)
+}
+
+func WriteSummary(w io.Writer, s Summary) {
+	fmt.Fprintln(w, "\nVulnerability summary:")
+	fmt.Fprintf(w, "  Critical: %d\n", s.Critical)
+	fmt.Fprintf(w, "  High:     %d\n", s.High)
+	fmt.Fprintf(w, "  Medium:   %d\n", s.Medium)
+	fmt.Fprintf(w, "  Low:      %d\n", s.Low)
+	fmt.Fprintf(w, "  Total:    %d\n", s.Total)
+	if s.KnownExploited > 0 {
+		fmt.Fprintf(w, "  Known exploited (KEV): %d\n", s.KnownExploited)
+	}
+}
+
+// PrintCoverage lists what the scan could not check, grouped by reason,
+// so an empty report is never mistaken for a clean one.
+func PrintCoverage(c *Coverage) {
+	WriteCoverage(os.Stdout, c)
+}
+
+func WriteCoverage(w io.Writer, c *Coverage) {
+	if c == nil {
+		return
+	}
+	fmt.Fprintln(w, "\nCoverage:")
+	if c.SBOMSource != "" {
+		fmt.Fprintf(w, "  SBOM:      %s (%s)\n", c.SBOMSource, c.SBOMFormat)
+	}
+	fmt.Fprintf(w, "  Packages:  %d\n", c.Packages)
+	fmt.Fprintf(w, "  Scanned:   %d (%.1f%%)\n", c.Queryable, c.Percent)
+	fmt.Fprintf(w, "  Skipped:   %d\n", len(c.Skipped))
+
+	ecos := make([]string, 0, len(c.Ecosystems))
+	for eco := range c.Ecosystems {
+		ecos = append(ecos, eco)
+	}
+	sort.Strings(ecos)
+	if len(ecos) > 0 {
+		fmt.Fprintln(w, "\n  By ecosystem:")
+	}
+	for _, eco := range ecos {
+		e := c.Ecosystems[eco]
+		fmt.Fprintf(w, "    %-20s %4d packages, %4d scanned, %4d skipped\n", eco, e.Packages, e.Queryable, e.Skipped)
+	}
+
+	byReason := map[SkipReason][]SkippedPackage{}
+	var reasons []SkipReason
+	for _, p := range c.Skipped {
+		if _, ok := byReason[p.Reason]; !ok {
+			reasons = append(reasons, p.Reason)
+		}
+		byReason[p.Reason] = append(byReason[p.Reason], p)
+	}
+	sort.Slice(reasons, func(i, j int) bool { return reasons[i] < reasons[j] })
+
+	for _, r := range reasons {
+		fmt.Fprintf(w, "\n  Skipped (%s): %d\n", r, c.SkippedByReason[r])
+		for _, p := range byReason[r] {
+			line := "    - " + p.Name
+			if p.Version != "" {
+				line += "@" + p.Version
+			}
+			if p.Type != "" {
+				line += " [" + p.Type + "]"
+			}
+			if p.Detail != "" {
+				line += ": " + p.Detail
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+func PrintJSON(r ScanReport) error {
+	return WriteJSON(os.Stdout, r)
+}
+
+func WriteJSON(w io.Writer, r ScanReport) error {
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
-	return enc.Encode(out)
+	return enc.Encode(r)
 }